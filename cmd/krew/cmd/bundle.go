@@ -0,0 +1,244 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/cmd/krew/cmd/internal"
+	"sigs.k8s.io/krew/internal/bundle"
+	"sigs.k8s.io/krew/internal/download"
+	"sigs.k8s.io/krew/internal/index/indexoperations"
+	"sigs.k8s.io/krew/internal/index/indexscanner"
+	"sigs.k8s.io/krew/internal/index/validation"
+	"sigs.k8s.io/krew/internal/installation"
+	"sigs.k8s.io/krew/internal/network"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+var (
+	bundlePluginsFile *string
+	bundlePlatform    *string
+	bundleOutput      *string
+)
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create and install plugin bundles for hosts without network access",
+	Long: `Pack plugins into a single file on a host with network access, and install
+them from that file on hosts without it.
+
+A bundle contains the manifests of the plugins and their archives for one
+platform. The archives are verified against the sha256 sums in the manifests
+when the bundle is created and again when it is installed.`,
+	Args: cobra.NoArgs,
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a bundle of plugins",
+	Long: `Create a bundle with the plugins listed in a file.
+
+The file lists one plugin per line, as NAME or INDEX/NAME. Empty lines and
+lines starting with # are ignored. The archives are selected for --platform,
+which defaults to the platform of this host.`,
+	Example: `  kubectl krew bundle create -f plugins.txt --platform linux/amd64 -o bundle.tar`,
+	Args:    cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		platform, err := installation.ParseOSArch(*bundlePlatform)
+		if err != nil {
+			return err
+		}
+		names, err := readPluginList(*bundlePluginsFile)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return errors.Errorf("no plugins listed in %q", *bundlePluginsFile)
+		}
+
+		var sources []bundle.Source
+		for _, name := range names {
+			indexName, pluginName, err := indexoperations.ResolvePluginName(paths, name)
+			if err != nil {
+				return err
+			}
+			if !validation.IsSafePluginName(pluginName) {
+				return unsafePluginNameErr(pluginName)
+			}
+			plugin, err := indexoperations.LoadPlugin(paths, indexName, pluginName)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return errors.Errorf("plugin %q does not exist in the plugin index", name)
+				}
+				return errors.Wrapf(err, "failed to load plugin %q from the index", name)
+			}
+			klog.V(2).Infof("Will bundle plugin: %s/%s", indexName, pluginName)
+			sources = append(sources, bundle.Source{Plugin: plugin, Index: indexName})
+		}
+
+		f, err := os.Create(*bundleOutput)
+		if err != nil {
+			return errors.Wrap(err, "failed to create bundle file")
+		}
+		err = bundle.Create(f, platform, sources, download.HTTPFetcher{})
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(*bundleOutput)
+			return errors.Wrap(err, "failed to create bundle")
+		}
+		fmt.Fprintf(os.Stderr, "Created bundle %q with %d plugins for %s.\n", *bundleOutput, len(sources), platform)
+		return nil
+	},
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if network.Offline() {
+			klog.V(1).Infof("offline mode, skipping updating local copy of plugin index")
+			return nil
+		}
+		return ensureIndexes(cmd, args)
+	},
+}
+
+var bundleInstallCmd = &cobra.Command{
+	Use:   "install BUNDLE",
+	Short: "Install the plugins in a bundle",
+	Long: `Install the plugins in a bundle created with "kubectl krew bundle create".
+
+The bundle must have been created for the platform of this host. It does not
+need network access or a local copy of the plugin indexes. The receipts of the
+installed plugins record the index that each plugin was bundled from.
+
+Remarks:
+  If a plugin is already installed, it will be skipped.
+  Failure to install a plugin will not stop the installation of other plugins.`,
+	Example: `  kubectl krew bundle install bundle.tar`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to open bundle")
+		}
+		defer f.Close()
+
+		dir, err := ioutil.TempDir("", "krew-bundle")
+		if err != nil {
+			return errors.Wrap(err, "failed to create temporary directory")
+		}
+		defer os.RemoveAll(dir)
+
+		m, err := bundle.Extract(f, dir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to extract bundle %q", args[0])
+		}
+		platform, err := installation.ParseOSArch(m.Platform)
+		if err != nil {
+			return errors.Wrap(err, "invalid platform in bundle")
+		}
+		if env := installation.OSArch(); !platform.Compatible(env) {
+			return errors.Errorf("bundle was created for platform %q, but this host is %q", m.Platform, env)
+		}
+
+		var failed []string
+		var returnErr error
+		for _, e := range m.Plugins {
+			if err := installFromBundle(dir, e); err != nil {
+				klog.Warningf("failed to install plugin %q: %v", e.Name, err)
+				if returnErr == nil {
+					returnErr = err
+				}
+				failed = append(failed, e.Name)
+			}
+		}
+		if len(failed) > 0 {
+			return errors.Wrapf(returnErr, "failed to install some plugins: %+v", failed)
+		}
+		return nil
+	},
+}
+
+// installFromBundle installs a plugin from a bundle extracted to dir.
+func installFromBundle(dir string, e bundle.Entry) error {
+	plugin, err := indexscanner.ReadPluginFromFile(bundle.Path(dir, e.Manifest))
+	if err != nil {
+		return errors.Wrap(err, "failed to load plugin manifest from bundle")
+	}
+	if plugin.Name != e.Name || !validation.IsSafePluginName(plugin.Name) {
+		return unsafePluginNameErr(plugin.Name)
+	}
+	if !indexoperations.IsValidIndexName(e.Index) {
+		return errors.Errorf("invalid index name %q in bundle", e.Index)
+	}
+
+	fmt.Fprintf(os.Stderr, "Installing plugin: %s\n", plugin.Name)
+	err = installation.Install(paths, plugin, e.Index, installation.InstallOpts{
+		ArchiveFileOverride: bundle.Path(dir, e.Archive),
+		Detached:            true,
+	})
+	if err == installation.ErrIsAlreadyInstalled {
+		klog.Warningf("Skipping plugin %q, it is already installed", plugin.Name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Installed plugin: %s\n", plugin.Name)
+	if e.Index == constants.DefaultIndexName {
+		internal.PrintSecurityNotice(plugin.Name)
+	}
+	return nil
+}
+
+// readPluginList reads plugin names from a file with one name per line,
+// ignoring empty lines and comments.
+func readPluginList(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open plugin list")
+	}
+	defer f.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names, errors.Wrapf(scanner.Err(), "failed to read plugin list %q", file)
+}
+
+func init() {
+	bundlePluginsFile = bundleCreateCmd.Flags().StringP("file", "f", "", "file that lists the plugins to bundle")
+	bundlePlatform = bundleCreateCmd.Flags().String("platform", installation.OSArch().String(), "os/arch[/variant][/libc] to bundle the plugin archives for")
+	bundleOutput = bundleCreateCmd.Flags().StringP("output", "o", "krew-bundle.tar", "path of the bundle to create")
+	_ = bundleCreateCmd.MarkFlagRequired("file")
+
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCmd.AddCommand(bundleInstallCmd)
+	rootCmd.AddCommand(bundleCmd)
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion SHELL",
+	Short: "Generate shell completion scripts for krew",
+	Long: `Generate the shell completion script for krew commands.
+
+Supported shells are bash, zsh, fish and powershell. Load the script in
+your shell's startup file to complete krew commands and flags.
+
+Examples:
+  To load completions for krew in the current bash session, run:
+    source <(kubectl krew completion bash)
+
+  To load completions for krew for every zsh session, run once:
+    kubectl krew completion zsh > "${fpath[1]}/_krew"
+
+Remarks:
+  Completion scripts of installed plugins are linked next to the plugins as
+  kubectl_complete-<plugin> executables, where kubectl finds them itself.`,
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	// Generating a script needs neither the krew home nor the index.
+	PersistentPreRun: func(*cobra.Command, []string) {},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return writeCompletion(os.Stdout, cmd.Root(), args[0])
+	},
+}
+
+// writeCompletion writes the completion script for the commands under root
+// in the given shell to w.
+func writeCompletion(w io.Writer, root *cobra.Command, shell string) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletionV2(w, true)
+	case "zsh":
+		return root.GenZshCompletion(w)
+	case "fish":
+		return root.GenFishCompletion(w, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(w)
+	default:
+		return errors.Errorf("unsupported shell %q", shell)
+	}
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
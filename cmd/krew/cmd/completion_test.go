@@ -0,0 +1,44 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func Test_writeCompletion(t *testing.T) {
+	root := &cobra.Command{Use: "krew"}
+	root.AddCommand(&cobra.Command{Use: "install", Run: func(*cobra.Command, []string) {}})
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeCompletion(&buf, root, shell); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(buf.String(), "krew") {
+				t.Errorf("completion script for %s does not mention the command:\n%s", shell, buf.String())
+			}
+		})
+	}
+
+	if err := writeCompletion(&bytes.Buffer{}, root, "tcsh"); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
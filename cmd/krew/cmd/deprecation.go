@@ -0,0 +1,39 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/krew/pkg/index"
+)
+
+// isDeprecated tells whether a plugin manifest marks the plugin as deprecated.
+func isDeprecated(spec index.PluginSpec) bool {
+	return spec.Deprecated != "" || spec.ReplacedBy != ""
+}
+
+// deprecationNotice describes why the plugin name in an index is deprecated
+// and which plugin replaces it.
+func deprecationNotice(name, indexName, message, replacedBy string) string {
+	s := fmt.Sprintf("plugin %q is deprecated", displayPluginName(name, indexName))
+	if message != "" {
+		s += ": " + message
+	}
+	if replacedBy != "" {
+		s += fmt.Sprintf(" (replaced by %q)", displayPluginName(replacedBy, indexName))
+	}
+	return s
+}
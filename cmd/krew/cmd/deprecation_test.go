@@ -0,0 +1,42 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func Test_deprecationNotice(t *testing.T) {
+	tests := []struct {
+		name, index, message, replacedBy string
+		want                             string
+	}{
+		{
+			name: "foo", index: "default",
+			want: `plugin "foo" is deprecated`,
+		},
+		{
+			name: "foo", index: "default", message: "no longer maintained",
+			want: `plugin "foo" is deprecated: no longer maintained`,
+		},
+		{
+			name: "foo", index: "corp", message: "renamed", replacedBy: "bar",
+			want: `plugin "corp/foo" is deprecated: renamed (replaced by "corp/bar")`,
+		},
+	}
+	for _, tt := range tests {
+		if got := deprecationNotice(tt.name, tt.index, tt.message, tt.replacedBy); got != tt.want {
+			t.Errorf("deprecationNotice(%q, %q) = %q, want %q", tt.name, tt.index, got, tt.want)
+		}
+	}
+}
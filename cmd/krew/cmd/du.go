@@ -0,0 +1,181 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/krew/internal/diskusage"
+	"sigs.k8s.io/krew/internal/environment"
+)
+
+type pluginDiskUsage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	Bytes   int64  `json:"bytes"`
+}
+
+type indexDiskUsage struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"`
+}
+
+type diskUsageReport struct {
+	typeMeta
+	Plugins       []pluginDiskUsage `json:"plugins"`
+	Indexes       []indexDiskUsage  `json:"indexes"`
+	CacheBytes    int64             `json:"cacheBytes"`
+	ReceiptsBytes int64             `json:"receiptsBytes"`
+	TotalBytes    int64             `json:"totalBytes"`
+
+	cachePath, receiptsPath, basePath string
+}
+
+func init() {
+	var output *string
+
+	// duCmd represents the du command
+	duCmd := &cobra.Command{
+		Use:   "du",
+		Short: "Show disk usage of installed plugins and indexes",
+		Long: `Show the disk space used by krew.
+
+This command reports the size of every installed plugin version, every
+local copy of a plugin index, the caches and the total size of the krew root
+directory. Files that do not belong to a plugin, an index or a receipt (the
+parsed plugin lists, index state and configuration) are reported as cache, so
+the rows add up to the total.
+
+Examples:
+  To print the disk usage as a table:
+    kubectl krew du
+
+  To print the disk usage as JSON:
+    kubectl krew du -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := parseOutputFormat(*output)
+			if err != nil {
+				return err
+			}
+			report, err := computeDiskUsage(paths)
+			if err != nil {
+				return err
+			}
+			return printObject(os.Stdout, format, report)
+		},
+	}
+
+	output = addOutputFlag(duCmd)
+	rootCmd.AddCommand(duCmd)
+}
+
+// computeDiskUsage walks the krew directories and collects the disk usage of
+// each plugin version and index. The remaining files in the krew root are
+// reported as cache, so that all rows add up to TotalBytes.
+func computeDiskUsage(p environment.Paths) (diskUsageReport, error) {
+	report := diskUsageReport{
+		typeMeta: newTypeMeta("DiskUsage"),
+		Plugins:  []pluginDiskUsage{},
+		Indexes:  []indexDiskUsage{},
+
+		cachePath:    p.CachePath(),
+		receiptsPath: p.InstallReceiptsPath(),
+		basePath:     p.BasePath(),
+	}
+
+	plugins, err := diskusage.Subdirs(p.InstallPath())
+	if err != nil {
+		return report, errors.Wrap(err, "failed to compute disk usage of installed plugins")
+	}
+	for _, plugin := range plugins {
+		versions, err := diskusage.Subdirs(plugin.Path)
+		if err != nil {
+			return report, errors.Wrapf(err, "failed to compute disk usage of plugin %q", plugin.Name)
+		}
+		for _, v := range versions {
+			report.Plugins = append(report.Plugins, pluginDiskUsage{
+				Name:    plugin.Name,
+				Version: v.Name,
+				Path:    v.Path,
+				Bytes:   v.Bytes,
+			})
+		}
+	}
+
+	indexes, err := diskusage.Subdirs(p.IndexBase())
+	if err != nil {
+		return report, errors.Wrap(err, "failed to compute disk usage of indexes")
+	}
+	for _, idx := range indexes {
+		report.Indexes = append(report.Indexes, indexDiskUsage{
+			Name:  idx.Name,
+			Path:  idx.Path,
+			Bytes: idx.Bytes,
+		})
+	}
+
+	if report.ReceiptsBytes, err = diskusage.Size(p.InstallReceiptsPath()); err != nil && !os.IsNotExist(err) {
+		return report, errors.Wrap(err, "failed to compute disk usage of receipts")
+	}
+	if report.TotalBytes, err = diskusage.Size(p.BasePath()); err != nil {
+		return report, errors.Wrap(err, "failed to compute total disk usage")
+	}
+
+	// Everything that is not a plugin, an index or a receipt is attributed to
+	// the caches: the parsed plugin lists, the index state, indexes.yaml and
+	// any other files krew keeps in its root.
+	report.CacheBytes = report.TotalBytes - report.ReceiptsBytes
+	for _, pl := range report.Plugins {
+		report.CacheBytes -= pl.Bytes
+	}
+	for _, idx := range report.Indexes {
+		report.CacheBytes -= idx.Bytes
+	}
+	return report, nil
+}
+
+func (r diskUsageReport) tableColumns(wide bool) []string {
+	if wide {
+		return []string{"KIND", "NAME", "VERSION", "SIZE", "PATH"}
+	}
+	return []string{"KIND", "NAME", "VERSION", "SIZE"}
+}
+
+func (r diskUsageReport) tableRows(wide bool) [][]string {
+	var rows [][]string
+	addRow := func(kind, name, version string, bytes int64, path string) {
+		row := []string{kind, name, version, diskusage.HumanReadable(bytes)}
+		if wide {
+			row = append(row, path)
+		}
+		rows = append(rows, row)
+	}
+	for _, pl := range r.Plugins {
+		addRow("plugin", pl.Name, pl.Version, pl.Bytes, pl.Path)
+	}
+	for _, idx := range r.Indexes {
+		addRow("index", idx.Name, "", idx.Bytes, idx.Path)
+	}
+	addRow("cache", "", "", r.CacheBytes, r.cachePath)
+	addRow("receipts", "", "", r.ReceiptsBytes, r.receiptsPath)
+	addRow("total", "", "", r.TotalBytes, r.basePath)
+	return rows
+}
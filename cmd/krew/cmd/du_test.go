@@ -0,0 +1,54 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/testutil"
+)
+
+func Test_computeDiskUsage(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+
+	tmpDir.Write("store/foo/v1.0.0/foo", make([]byte, 100))
+	tmpDir.Write("index/default/plugins/foo.yaml", make([]byte, 20))
+	tmpDir.Write("receipts/foo.yaml", make([]byte, 5))
+	tmpDir.Write("cache/index/default.json", make([]byte, 7))
+	tmpDir.Write("state/index/default.json", make([]byte, 3))
+	tmpDir.Write("indexes.yaml", make([]byte, 2))
+
+	report, err := computeDiskUsage(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Plugins) != 1 || report.Plugins[0].Bytes != 100 {
+		t.Errorf("unexpected plugins in report: %+v", report.Plugins)
+	}
+	if len(report.Indexes) != 1 || report.Indexes[0].Bytes != 20 {
+		t.Errorf("unexpected indexes in report: %+v", report.Indexes)
+	}
+	if report.ReceiptsBytes != 5 {
+		t.Errorf("ReceiptsBytes = %d, want 5", report.ReceiptsBytes)
+	}
+	if report.CacheBytes != 12 {
+		t.Errorf("CacheBytes = %d, want 12", report.CacheBytes)
+	}
+	if report.TotalBytes != 137 {
+		t.Errorf("TotalBytes = %d, want 137", report.TotalBytes)
+	}
+}
@@ -15,7 +15,11 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -23,13 +27,20 @@ import (
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/krew/cmd/krew/cmd/internal"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
 	"sigs.k8s.io/krew/internal/index/indexoperations"
+	"sigs.k8s.io/krew/internal/index/indextrust"
 	"sigs.k8s.io/krew/internal/installation"
 	"sigs.k8s.io/krew/pkg/constants"
 )
 
 var (
 	forceIndexDelete    *bool
+	indexListOutput     *string
+	indexAddRef         *string
+	indexAddPriority    *int
+	indexAddSigningKeys *[]string
+	indexAddVerify      *string
 	errInvalidIndexName = errors.New("invalid index name")
 )
 
@@ -46,38 +57,78 @@ var indexListCmd = &cobra.Command{
 	Short: "List configured indexes",
 	Long: `Print a list of configured indexes.
 
-This command prints a list of indexes. It shows the name and the remote URL for
-each configured index in table format.`,
+This command prints a list of indexes. It shows the name, the remote URL, the
+pinned ref (if any) and the priority of each configured index in table format,
+or in the format given with --output.
+
+The status columns describe the local copy of each index: the time it was last
+updated successfully, its current revision (such as the commit of a git index),
+the number of plugins and the number of plugin manifests that failed to parse.
+STATE is "dirty" if files in the local copy were changed and "diverged" if it is
+not at the revision of its ref, e.g. because of local commits. Both are fixed
+by updating the index.`,
 	Args: cobra.NoArgs,
 	RunE: func(_ *cobra.Command, _ []string) error {
+		format, err := parseOutputFormat(*indexListOutput)
+		if err != nil {
+			return err
+		}
 		indexes, err := indexoperations.ListIndexes(paths)
 		if err != nil {
 			return errors.Wrap(err, "failed to list indexes")
 		}
-
-		var rows [][]string
-		for _, index := range indexes {
-			rows = append(rows, []string{index.Name, index.URL})
+		statuses := make(map[string]indexoperations.Status, len(indexes))
+		for _, idx := range indexes {
+			s, err := indexoperations.IndexStatus(paths, idx.Name)
+			if err != nil {
+				klog.Warningf("failed to get the status of index %q: %v", idx.Name, err)
+				continue
+			}
+			statuses[idx.Name] = s
 		}
-		return printTable(os.Stdout, []string{"INDEX", "URL"}, rows)
+		return printObject(os.Stdout, format, newIndexList(indexes, statuses))
 	},
 }
 
 var indexAddCmd = &cobra.Command{
-	Use:     "add",
-	Short:   "Add a new index",
-	Long:    "Configure a new index to install plugins from.",
-	Example: "kubectl krew index add default " + constants.DefaultIndexURI,
-	Args:    cobra.ExactArgs(2),
-	RunE: func(_ *cobra.Command, args []string) error {
+	Use:   "add",
+	Short: "Add a new index",
+	Long: `Configure a new index to install plugins from.
+
+By default, the index tracks the default branch of the remote repository. Use
+--ref to pin the index to a branch, tag or commit instead. Updates of an index
+pinned to a branch follow that branch, while indexes pinned to a tag or a
+commit stay at that revision.
+
+Plugins given without an index name are looked up in the indexes from the
+highest to the lowest --priority. The default index has priority ` + strconv.Itoa(indexconfig.DefaultIndexPriority) + ` and
+other indexes have priority 0, unless specified otherwise.
+
+With --signing-key, the index is verified when it is added and after every
+update, and plugins are not loaded from it if it fails verification. Git
+indexes must have a HEAD commit signed with one of the keys, other indexes a
+signed SHA256SUMS snapshot manifest in their plugins directory.`,
+	Example: `  kubectl krew index add default ` + constants.DefaultIndexURI + `
+  kubectl krew index add corp https://example.com/corp/krew-index.git --ref release-2026
+  kubectl krew index add corp https://example.com/corp/krew-index.git --priority 200
+  kubectl krew index add corp https://example.com/corp/krew-index.git --signing-key corp.asc`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		if !indexoperations.IsValidIndexName(name) {
 			return errInvalidIndexName
 		}
-		err := indexoperations.AddIndex(paths, name, args[1])
+		priority := indexconfig.DefaultPriority(name)
+		if cmd.Flags().Changed("priority") {
+			priority = *indexAddPriority
+		}
+		trust, err := indexTrust(*indexAddSigningKeys, *indexAddVerify)
 		if err != nil {
 			return err
 		}
+		if err := indexoperations.AddIndex(paths, name, args[1], *indexAddRef, priority, trust); err != nil {
+			return err
+		}
 		internal.PrintWarning(os.Stderr, `You have added a new index from %q
 The plugins in this index are not audited for security by the Krew maintainers.
 Install them at your own risk.
@@ -86,6 +137,86 @@ Install them at your own risk.
 	},
 }
 
+var indexSetPriorityCmd = &cobra.Command{
+	Use:   "set-priority NAME PRIORITY",
+	Short: "Change the priority of an index",
+	Long: `Change the priority of a configured index.
+
+Plugins given without an index name are looked up in the indexes from the
+highest to the lowest priority. If more than one index with the same priority
+has the plugin, the index name must be given explicitly.`,
+	Example: `  kubectl krew index set-priority corp 200`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		name := args[0]
+		if !indexoperations.IsValidIndexName(name) {
+			return errInvalidIndexName
+		}
+		priority, err := strconv.Atoi(args[1])
+		if err != nil {
+			return errors.Errorf("invalid priority %q, must be an integer", args[1])
+		}
+		err = indexoperations.SetIndexPriority(paths, name, priority)
+		if os.IsNotExist(err) {
+			return errors.Errorf("index %q does not exist", name)
+		}
+		return errors.Wrap(err, "failed to set the index priority")
+	},
+}
+
+var indexRenameCmd = &cobra.Command{
+	Use:   "rename OLD NEW",
+	Short: "Rename an index",
+	Long: `Rename a configured index.
+
+Plugins installed from the index are updated to refer to the new name, so
+that they can still be upgraded from it. The default index cannot be renamed.`,
+	Example: `  kubectl krew index rename corp corp-mirror`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		for _, name := range args {
+			if !indexoperations.IsValidIndexName(name) {
+				return errInvalidIndexName
+			}
+		}
+		err := indexoperations.RenameIndex(paths, args[0], args[1])
+		if os.IsNotExist(err) {
+			return errors.Errorf("index %q does not exist", args[0])
+		}
+		return errors.Wrap(err, "failed to rename the index")
+	},
+}
+
+var indexSetURLCmd = &cobra.Command{
+	Use:   "set-url NAME URL",
+	Short: "Change the URL of an index",
+	Long: `Change the URL that an index is updated from, and update the index from it.
+
+This keeps the plugins installed from the index, for example when the index
+moves to an internal mirror. The new URL must be of the same type as the old
+one, e.g. both git repositories. If the update from the new URL fails, the
+index keeps its old URL.`,
+	Example: `  kubectl krew index set-url corp https://git.example.com/mirrors/krew-index.git`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		name := args[0]
+		if !indexoperations.IsValidIndexName(name) {
+			return errInvalidIndexName
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), defaultIndexUpdateTimeout)
+		defer cancel()
+		err := indexoperations.SetIndexURL(ctx, paths, name, args[1])
+		if os.IsNotExist(err) {
+			return errors.Errorf("index %q does not exist", name)
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to set the index URL")
+		}
+		fmt.Fprintf(os.Stderr, "Updated index %q from %q.\n", name, args[1])
+		return nil
+	},
+}
+
 var indexDeleteCmd = &cobra.Command{
 	Use:   "remove",
 	Short: "Remove a configured index",
@@ -134,12 +265,43 @@ Removing indexes while there are plugins installed from is not recommended
 	return errors.Wrap(err, "error while removing the plugin index")
 }
 
+// indexTrust returns the trust configuration for the given signing key files
+// and verification method, or nil if no keys are given.
+func indexTrust(keyFiles []string, method string) (*indexconfig.Trust, error) {
+	if len(keyFiles) == 0 {
+		if method != "" {
+			return nil, errors.New("--verify requires --signing-key")
+		}
+		return nil, nil
+	}
+	if method != "" && !indextrust.IsValidMethod(method) {
+		return nil, errors.Errorf("invalid verification method %q, must be %q or %q", method, indextrust.MethodCommit, indextrust.MethodSnapshot)
+	}
+	trust := &indexconfig.Trust{Method: method}
+	for _, f := range keyFiles {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the absolute path of %q", f)
+		}
+		trust.SigningKeys = append(trust.SigningKeys, abs)
+	}
+	return trust, nil
+}
+
 func init() {
 	forceIndexDelete = indexDeleteCmd.Flags().Bool("force", false,
 		"Remove index even if it has plugins currently installed (may result in unsupported behavior)")
+	indexListOutput = addOutputFlag(indexListCmd)
+	indexAddRef = indexAddCmd.Flags().String("ref", "", "Pin the index to a branch, tag or commit")
+	indexAddSigningKeys = indexAddCmd.Flags().StringArray("signing-key", nil, "Only trust the index if it is signed with the OpenPGP public key in this file (can be repeated)")
+	indexAddVerify = indexAddCmd.Flags().String("verify", "", `Verify the signature of the HEAD "commit" or of a "snapshot" manifest (default: "commit" for git indexes, "snapshot" otherwise)`)
+	indexAddPriority = indexAddCmd.Flags().Int("priority", 0, "Priority of the index when looking up plugins given without an index name, higher is searched first")
 
 	indexCmd.AddCommand(indexAddCmd)
 	indexCmd.AddCommand(indexListCmd)
 	indexCmd.AddCommand(indexDeleteCmd)
+	indexCmd.AddCommand(indexSetPriorityCmd)
+	indexCmd.AddCommand(indexRenameCmd)
+	indexCmd.AddCommand(indexSetURLCmd)
 	rootCmd.AddCommand(indexCmd)
 }
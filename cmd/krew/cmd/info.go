@@ -18,62 +18,206 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"text/tabwriter"
+	"time"
 	"unicode"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"sigs.k8s.io/krew/internal/index/indexscanner"
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/index/indexoperations"
 	"sigs.k8s.io/krew/internal/installation"
-	"sigs.k8s.io/krew/internal/pathutil"
+	"sigs.k8s.io/krew/internal/installation/receipt"
 	"sigs.k8s.io/krew/pkg/index"
 )
 
+var (
+	infoOutput   *string
+	infoPlatform *string
+)
+
 // infoCmd represents the info command
 var infoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "Show information about an available plugin",
-	Long:  `Show detailed information about an available plugin.`,
+	Long: `Show detailed information about an available plugin.
+
+The information from the plugin index is merged with the details of the
+installed copy of the plugin, if it is installed from the same index. All
+platforms supported by the plugin are listed, and the one selected for the
+current os/arch (or the one given with --platform) is marked.`,
 	Example: `  kubectl krew info PLUGIN
-  kubectl krew info INDEX/PLUGIN`,
+  kubectl krew info INDEX/PLUGIN
+  kubectl krew info PLUGIN -o yaml
+  kubectl krew info PLUGIN --platform=darwin/arm64`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		index, plugin := pathutil.CanonicalPluginName(args[0])
+		format, err := parseOutputFormat(*infoOutput)
+		if err != nil {
+			return err
+		}
+		env := installation.OSArch()
+		if *infoPlatform != "" {
+			if env, err = installation.ParseOSArch(*infoPlatform); err != nil {
+				return errors.Wrap(err, "invalid --platform value")
+			}
+		}
+		index, plugin, err := indexoperations.ResolvePluginName(paths, args[0])
+		if err != nil {
+			return err
+		}
 
-		p, err := indexscanner.LoadPluginByName(paths.IndexPluginsPath(index), plugin)
+		p, err := indexoperations.LoadPlugin(paths, index, plugin)
 		if os.IsNotExist(err) {
 			return errors.Errorf("plugin %q not found in index %q", args[0], index)
 		} else if err != nil {
 			return errors.Wrap(err, "failed to load plugin manifest")
 		}
-		printPluginInfo(os.Stdout, index, p)
-		return nil
+		info, err := pluginInfo(paths, p, index, env)
+		if err != nil {
+			return err
+		}
+		if format.isHumanReadable() {
+			printPluginInfo(os.Stdout, info)
+			return nil
+		}
+		return printObject(os.Stdout, format, info)
 	},
 	PreRunE: checkIndex,
 	Args:    cobra.ExactArgs(1),
 }
 
-func printPluginInfo(out io.Writer, indexName string, plugin index.Plugin) {
-	fmt.Fprintf(out, "NAME: %s\n", plugin.Name)
-	fmt.Fprintf(out, "INDEX: %s\n", indexName)
-	if platform, ok, err := installation.GetMatchingPlatform(plugin.Spec.Platforms); err == nil && ok {
-		if platform.URI != "" {
+// pluginInfo merges the manifest of a plugin in the given index with its
+// install receipt. Platforms are matched against the given os/arch.
+func pluginInfo(p environment.Paths, plugin index.Plugin, indexName string, env installation.OSArchPair) (pluginObject, error) {
+	var (
+		platforms []platformObject
+		available bool
+	)
+	for _, platform := range plugin.Spec.Platforms {
+		o := platformObject{
+			Selector: metav1.FormatLabelSelector(platform.Selector),
+			URI:      platform.URI,
+			Sha256:   platform.Sha256,
+			Bin:      platform.Bin,
+		}
+		if !available {
+			_, ok, err := installation.GetMatchingPlatformFor([]index.Platform{platform}, env)
+			if err != nil {
+				return pluginObject{}, errors.Wrap(err, "failed to get the matching platform")
+			}
+			o.Matches, available = ok, ok
+		}
+		platforms = append(platforms, o)
+	}
+
+	var inst *installationObject
+	r, err := receipt.Load(p.PluginInstallReceiptPath(plugin.Name))
+	if err == nil && indexOf(r) == indexName {
+		inst = &installationObject{
+			Version: r.Spec.Version,
+			BinPath: installation.PluginBinPath(p, plugin.Name),
+		}
+		if !r.CreationTimestamp.IsZero() {
+			inst.InstalledAt = r.CreationTimestamp.UTC().Format(time.RFC3339)
+		}
+		if inst.Files, err = listFiles(p.PluginVersionInstallPath(plugin.Name, r.Spec.Version)); err != nil {
+			return pluginObject{}, errors.Wrapf(err, "failed to list installed files of plugin %q", plugin.Name)
+		}
+	} else if err != nil && !os.IsNotExist(err) {
+		return pluginObject{}, errors.Wrapf(err, "failed to load receipt of plugin %q", plugin.Name)
+	}
+
+	o := newPluginObject(plugin, indexName, inst != nil, available)
+	o.Platforms = platforms
+	o.Installation = inst
+	return o, nil
+}
+
+// listFiles returns the paths of the files in dir, relative to dir. A missing
+// directory has no files.
+func listFiles(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
+
+func printPluginInfo(out io.Writer, info pluginObject) {
+	fmt.Fprintf(out, "NAME: %s\n", info.Name)
+	fmt.Fprintf(out, "INDEX: %s\n", info.Index)
+	for _, platform := range info.Platforms {
+		if platform.Matches && platform.URI != "" {
 			fmt.Fprintf(out, "URI: %s\n", platform.URI)
 			fmt.Fprintf(out, "SHA256: %s\n", platform.Sha256)
 		}
 	}
-	if plugin.Spec.Version != "" {
-		fmt.Fprintf(out, "VERSION: %s\n", plugin.Spec.Version)
+	if info.Version != "" {
+		fmt.Fprintf(out, "VERSION: %s\n", info.Version)
 	}
-	if plugin.Spec.Homepage != "" {
-		fmt.Fprintf(out, "HOMEPAGE: %s\n", plugin.Spec.Homepage)
+	if info.Deprecated != "" {
+		fmt.Fprintf(out, "DEPRECATED: %s\n", info.Deprecated)
+	} else if info.ReplacedBy != "" {
+		fmt.Fprintln(out, "DEPRECATED: yes")
 	}
-	if plugin.Spec.Description != "" {
-		fmt.Fprintf(out, "DESCRIPTION: \n%s\n", plugin.Spec.Description)
+	if info.ReplacedBy != "" {
+		fmt.Fprintf(out, "REPLACED BY: %s\n", displayPluginName(info.ReplacedBy, info.Index))
 	}
-	if plugin.Spec.Caveats != "" {
-		fmt.Fprintf(out, "CAVEATS:\n%s\n", indent(plugin.Spec.Caveats))
+	if info.Homepage != "" {
+		fmt.Fprintf(out, "HOMEPAGE: %s\n", info.Homepage)
+	}
+	if info.Description != "" {
+		fmt.Fprintf(out, "DESCRIPTION: \n%s\n", info.Description)
+	}
+	if info.Caveats != "" {
+		fmt.Fprintf(out, "CAVEATS:\n%s\n", indent(info.Caveats))
+	}
+	if len(info.Platforms) > 0 {
+		fmt.Fprintln(out, "PLATFORMS:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, platform := range info.Platforms {
+			marker := " "
+			if platform.Matches {
+				marker = "*"
+			}
+			fmt.Fprintf(w, " %s %s\t%s\n", marker, platform.Selector, platform.URI)
+		}
+		w.Flush()
+	}
+	if info.Installation == nil {
+		fmt.Fprintln(out, "INSTALLED: no")
+		return
+	}
+	fmt.Fprintln(out, "INSTALLED: yes")
+	fmt.Fprintf(out, "INSTALLED VERSION: %s\n", info.Installation.Version)
+	if info.Installation.InstalledAt != "" {
+		fmt.Fprintf(out, "INSTALLED AT: %s\n", info.Installation.InstalledAt)
+	}
+	fmt.Fprintf(out, "BIN PATH: %s\n", info.Installation.BinPath)
+	if len(info.Installation.Files) > 0 {
+		fmt.Fprintln(out, "FILES:")
+		for _, f := range info.Installation.Files {
+			fmt.Fprintf(out, "  %s\n", f)
+		}
 	}
 }
 
@@ -95,5 +239,7 @@ func indent(s string) string {
 }
 
 func init() {
+	infoOutput = addOutputFlag(infoCmd)
+	infoPlatform = infoCmd.Flags().String("platform", "", "Show the artifact that would be installed on the given os/arch (e.g. darwin/arm64 or linux/amd64/musl)")
 	rootCmd.AddCommand(infoCmd)
 }
@@ -0,0 +1,103 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/installation"
+	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/constants"
+	"sigs.k8s.io/krew/pkg/index"
+)
+
+func Test_pluginInfo(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+
+	plugin := testutil.NewPlugin().WithName("foo").WithVersion("v1.0.0").WithPlatforms(
+		testutil.NewPlatform().WithOSArch("linux", "amd64").WithURI("https://example.com/linux.tar.gz").V(),
+		testutil.NewPlatform().WithOS("darwin").WithURI("https://example.com/darwin.tar.gz").V(),
+	).V()
+
+	info, err := pluginInfo(p, plugin, constants.DefaultIndexName, installation.OSArchPair{OS: "darwin", Arch: "arm64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Installed || info.Installation != nil {
+		t.Errorf("expected plugin not to be installed: %+v", info)
+	}
+	if !info.Available {
+		t.Error("expected plugin to be available on darwin/arm64")
+	}
+	var matching []string
+	for _, platform := range info.Platforms {
+		if platform.Matches {
+			matching = append(matching, platform.URI)
+		}
+	}
+	if diff := cmp.Diff([]string{"https://example.com/darwin.tar.gz"}, matching); diff != "" {
+		t.Errorf("matching platforms mismatch: %s", diff)
+	}
+
+	tmpDir.WriteYAML(filepath.Join("receipts", "foo"+constants.ManifestExtension),
+		testutil.NewReceipt().WithPlugin(plugin).WithStatus(index.ReceiptStatus{
+			Source: index.SourceIndex{Name: constants.DefaultIndexName},
+		}).V())
+	tmpDir.Write(filepath.Join("store", "foo", "v1.0.0", "foo"), nil)
+	tmpDir.Write(filepath.Join("store", "foo", "v1.0.0", "LICENSE"), nil)
+
+	info, err = pluginInfo(p, plugin, constants.DefaultIndexName, installation.OSArchPair{OS: "windows", Arch: "amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Available {
+		t.Error("expected plugin not to be available on windows/amd64")
+	}
+	if !info.Installed || info.Installation == nil {
+		t.Fatalf("expected plugin to be installed: %+v", info)
+	}
+	if diff := cmp.Diff([]string{"LICENSE", "foo"}, info.Installation.Files); diff != "" {
+		t.Errorf("installed files mismatch: %s", diff)
+	}
+	if info.Installation.BinPath != installation.PluginBinPath(p, "foo") {
+		t.Errorf("unexpected bin path %q", info.Installation.BinPath)
+	}
+
+	info, err = pluginInfo(p, plugin, "custom", installation.OSArch())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Installed {
+		t.Error("expected plugin installed from another index not to be reported as installed")
+	}
+}
+
+func Test_printPluginInfo_deprecated(t *testing.T) {
+	plugin := testutil.NewPlugin().WithName("foo").WithVersion("v1.0.0").WithReplacedBy("bar").V()
+	var buf bytes.Buffer
+	printPluginInfo(&buf, newPluginObject(plugin, "corp", false, true))
+	for _, want := range []string{"DEPRECATED: yes\n", "REPLACED BY: corp/bar\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected info to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
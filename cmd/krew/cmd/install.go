@@ -25,10 +25,11 @@ import (
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/krew/cmd/krew/cmd/internal"
+	"sigs.k8s.io/krew/internal/index/indexoperations"
 	"sigs.k8s.io/krew/internal/index/indexscanner"
 	"sigs.k8s.io/krew/internal/index/validation"
 	"sigs.k8s.io/krew/internal/installation"
-	"sigs.k8s.io/krew/internal/pathutil"
+	"sigs.k8s.io/krew/internal/network"
 	"sigs.k8s.io/krew/pkg/constants"
 	"sigs.k8s.io/krew/pkg/index"
 )
@@ -68,6 +69,8 @@ Examples:
 Remarks:
   If a plugin is already installed, it will be skipped.
   Failure to install a plugin will not stop the installation of other plugins.
+  In offline mode (--offline), plugin archives cannot be downloaded and are
+  not cached: provide the archive with --archive, or install a bundle.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var pluginNames = make([]string, len(args))
@@ -102,12 +105,15 @@ Remarks:
 
 			var install []pluginEntry
 			for _, name := range pluginNames {
-				indexName, pluginName := pathutil.CanonicalPluginName(name)
+				indexName, pluginName, err := indexoperations.ResolvePluginName(paths, name)
+				if err != nil {
+					return err
+				}
 				if !validation.IsSafePluginName(pluginName) {
 					return unsafePluginNameErr(pluginName)
 				}
 
-				plugin, err := indexscanner.LoadPluginByName(paths.IndexPluginsPath(indexName), pluginName)
+				plugin, err := indexoperations.LoadPlugin(paths, indexName, pluginName)
 				if err != nil {
 					if os.IsNotExist(err) {
 						return errors.Errorf("plugin %q does not exist in the plugin index", name)
@@ -127,7 +133,7 @@ Remarks:
 				}
 				install = append(install, pluginEntry{
 					p:         plugin,
-					indexName: "detached",
+					indexName: detachedIndexName,
 				})
 			} else if *manifestURL != "" {
 				plugin, err := readPluginFromURL(*manifestURL)
@@ -136,7 +142,7 @@ Remarks:
 				}
 				install = append(install, pluginEntry{
 					p:         plugin,
-					indexName: "detached",
+					indexName: detachedIndexName,
 				})
 			}
 
@@ -152,9 +158,13 @@ Remarks:
 			var returnErr error
 			for _, entry := range install {
 				plugin := entry.p
+				if isDeprecated(plugin.Spec) {
+					fmt.Fprintf(os.Stderr, "WARNING: %s\n", deprecationNotice(plugin.Name, entry.indexName, plugin.Spec.Deprecated, plugin.Spec.ReplacedBy))
+				}
 				fmt.Fprintf(os.Stderr, "Installing plugin: %s\n", plugin.Name)
 				err := installation.Install(paths, plugin, entry.indexName, installation.InstallOpts{
 					ArchiveFileOverride: *archiveFileOverride,
+					Detached:            entry.indexName == detachedIndexName,
 				})
 				if err == installation.ErrIsAlreadyInstalled {
 					klog.Warningf("Skipping plugin %q, it is already installed", plugin.Name)
@@ -195,6 +205,10 @@ Remarks:
 				klog.V(4).Infof("--no-update-index specified, skipping updating local copy of plugin index")
 				return nil
 			}
+			if network.Offline() {
+				klog.V(1).Infof("offline mode, skipping updating local copy of plugin index")
+				return nil
+			}
 			return ensureIndexes(cmd, args)
 		},
 	}
@@ -208,6 +222,9 @@ Remarks:
 }
 
 func readPluginFromURL(url string) (index.Plugin, error) {
+	if err := network.Check(fmt.Sprintf("downloading manifest %q", url)); err != nil {
+		return index.Plugin{}, err
+	}
 	klog.V(4).Infof("downloading manifest from url %s", url)
 	resp, err := http.Get(url)
 	if err != nil {
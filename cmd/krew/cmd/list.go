@@ -18,17 +18,21 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"sort"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
 
+	"sigs.k8s.io/krew/internal/index/indexoperations"
 	"sigs.k8s.io/krew/internal/installation"
+	"sigs.k8s.io/krew/pkg/index"
 )
 
 func init() {
+	var output *string
+
 	// listCmd represents the list command
 	listCmd := &cobra.Command{
 		Use:   "list",
@@ -38,38 +42,81 @@ func init() {
 Remarks:
   Redirecting the output of this command to a program or file will only print
   the names of the plugins installed. This output can be piped back to the
-  "install" command.`,
+  "install" command. Use --output to choose a different format.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := parseOutputFormat(*output)
+			if err != nil {
+				return err
+			}
 			receipts, err := installation.GetInstalledPluginReceipts(paths.InstallReceiptsPath())
 			if err != nil {
 				return errors.Wrap(err, "failed to find all installed versions")
 			}
 
+			plugins := newInstalledPluginList(receipts)
+
 			// return sorted list of plugin names when piped to other commands or file
-			if !isTerminal(os.Stdout) {
-				var names []string
-				for _, r := range receipts {
-					names = append(names, displayName(r.Plugin, indexOf(r)))
-				}
-				sort.Strings(names)
-				fmt.Fprintln(os.Stdout, strings.Join(names, "\n"))
+			if !cmd.Flags().Changed("output") && !isTerminal(os.Stdout) {
+				fmt.Fprintln(os.Stdout, strings.Join(plugins.names(), "\n"))
 				return nil
 			}
-
-			// print table
-			var rows [][]string
-			for _, r := range receipts {
-				rows = append(rows, []string{displayName(r.Plugin, indexOf(r)), r.Spec.Version})
+			addDeprecations(plugins)
+			if err := printObject(os.Stdout, format, plugins); err != nil {
+				return err
 			}
-			rows = sortByFirstColumn(rows)
-			return printTable(os.Stdout, []string{"PLUGIN", "VERSION"}, rows)
+			if format.isHumanReadable() {
+				printDeprecationWarnings(os.Stderr, plugins)
+			}
+			return nil
 		},
 		PreRunE: checkIndex,
 	}
 
+	output = addOutputFlag(listCmd)
 	rootCmd.AddCommand(listCmd)
 }
 
+// addDeprecations copies the deprecation fields from the manifests of the
+// installed plugins in their indexes. Indexes that cannot be loaded are
+// skipped.
+func addDeprecations(l installedPluginList) {
+	specs := make(map[string]map[string]index.PluginSpec)
+	for i := range l.Items {
+		item := &l.Items[i]
+		if item.Index == detachedIndexName {
+			continue
+		}
+		indexSpecs, ok := specs[item.Index]
+		if !ok {
+			indexSpecs = make(map[string]index.PluginSpec)
+			plugins, err := indexoperations.LoadPluginList(paths, item.Index)
+			if err != nil {
+				klog.V(1).Infof("WARNING: failed to load index %q: %v", item.Index, err)
+			}
+			for _, p := range plugins {
+				indexSpecs[p.Name] = p.Spec
+			}
+			specs[item.Index] = indexSpecs
+		}
+		spec := indexSpecs[item.Name]
+		item.Deprecated, item.ReplacedBy = spec.Deprecated, spec.ReplacedBy
+	}
+}
+
+// printDeprecationWarnings warns about installed plugins that are deprecated.
+func printDeprecationWarnings(out io.Writer, l installedPluginList) {
+	for _, p := range l.Items {
+		if p.Deprecated == "" && p.ReplacedBy == "" {
+			continue
+		}
+		fmt.Fprintf(out, "WARNING: %s\n", deprecationNotice(p.Name, p.Index, p.Deprecated, p.ReplacedBy))
+		if p.ReplacedBy != "" {
+			fmt.Fprintf(out, "Run \"kubectl krew upgrade --replace %s\" to install %q instead.\n",
+				p.Name, displayPluginName(p.ReplacedBy, p.Index))
+		}
+	}
+}
+
 func printTable(out io.Writer, columns []string, rows [][]string) error {
 	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
 	fmt.Fprint(w, strings.Join(columns, "\t"))
@@ -80,10 +127,3 @@ func printTable(out io.Writer, columns []string, rows [][]string) error {
 	}
 	return w.Flush()
 }
-
-func sortByFirstColumn(rows [][]string) [][]string {
-	sort.Slice(rows, func(a, b int) bool {
-		return rows[a][0] < rows[b][0]
-	})
-	return rows
-}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/cmd/krew/cmd/internal"
+	"sigs.k8s.io/krew/internal/index/indexoperations"
+	"sigs.k8s.io/krew/internal/index/validation"
+	"sigs.k8s.io/krew/internal/installation"
+	"sigs.k8s.io/krew/internal/network"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+var (
+	migrateToIndex       *string
+	migrateNoUpdateIndex *bool
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate PLUGIN --to-index NAME",
+	Short: "Move an installed plugin to another index",
+	Long: `Move an installed plugin to another index.
+
+Use this command when a plugin moves from one index to another. The plugin must
+exist in the target index. Upgrades of the plugin then use the target index.
+
+If the target index has a different version or archive of the plugin than the
+one installed, the plugin is reinstalled from the target index, even if its
+version is older. Otherwise, only the index of the installed plugin is changed.`,
+	Example: `  kubectl krew migrate ctx --to-index default
+  kubectl krew migrate ctx --to-index corp`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if isCanonicalName(name) {
+			return errors.New("migrate command does not support INDEX/PLUGIN syntax; just specify PLUGIN and --to-index")
+		} else if !validation.IsSafePluginName(name) {
+			return unsafePluginNameErr(name)
+		}
+		indexName := *migrateToIndex
+		if !indexoperations.IsValidIndexName(indexName) {
+			return errInvalidIndexName
+		}
+
+		plugin, err := indexoperations.LoadPlugin(paths, indexName, name)
+		if os.IsNotExist(err) {
+			return errors.Errorf("plugin %q does not exist in index %q", name, indexName)
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to load the plugin manifest for plugin %s", name)
+		}
+
+		klog.V(2).Infof("Migrating plugin %q to index %q", name, indexName)
+		if err := installation.Migrate(paths, plugin, indexName); err == installation.ErrIsNotInstalled {
+			return errors.Errorf("plugin %q is not installed", name)
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to migrate plugin %q", name)
+		}
+		fmt.Fprintf(os.Stderr, "Migrated plugin %s to index %q\n", name, indexName)
+		if indexName == constants.DefaultIndexName {
+			internal.PrintSecurityNotice(plugin.Name)
+		}
+		return nil
+	},
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if *migrateNoUpdateIndex {
+			klog.V(4).Infof("--no-update-index specified, skipping updating local copy of plugin index")
+			return nil
+		}
+		if network.Offline() {
+			klog.V(1).Infof("offline mode, skipping updating local copy of plugin index")
+			return nil
+		}
+		return ensureIndexes(cmd, args)
+	},
+}
+
+func init() {
+	migrateToIndex = migrateCmd.Flags().String("to-index", "", "Name of the index to move the plugin to")
+	_ = migrateCmd.MarkFlagRequired("to-index")
+	migrateNoUpdateIndex = migrateCmd.Flags().Bool("no-update-index", false, "(Experimental) do not update local copy of plugin index before migrating")
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -21,6 +21,10 @@ import (
 	"sigs.k8s.io/krew/pkg/index"
 )
 
+// detachedIndexName is the index name recorded in receipts of plugins that
+// were installed from a manifest file rather than from an index.
+const detachedIndexName = "detached"
+
 var canonicalNameRegex = regexp.MustCompile(`^[\w-]+/[\w-]+$`)
 
 // indexOf returns the index name of a receipt.
@@ -34,10 +38,16 @@ func indexOf(r index.Receipt) string {
 // displayName returns the display name of a Plugin.
 // The index name is omitted if it is the default index.
 func displayName(p index.Plugin, indexName string) string {
+	return displayPluginName(p.Name, indexName)
+}
+
+// displayPluginName returns the display name for the plugin name in the index.
+// The index name is omitted if it is the default index.
+func displayPluginName(name, indexName string) string {
 	if isDefaultIndex(indexName) {
-		return p.Name
+		return name
 	}
-	return indexName + "/" + p.Name
+	return indexName + "/" + name
 }
 
 func isDefaultIndex(name string) bool {
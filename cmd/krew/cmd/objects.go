@@ -0,0 +1,352 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"sigs.k8s.io/krew/internal/index/indexoperations"
+	"sigs.k8s.io/krew/pkg/index"
+)
+
+// outputAPIVersion is the apiVersion of objects printed in structured output
+// formats. Fields may be added to these objects, but existing fields must not
+// be renamed or removed without bumping the version.
+const outputAPIVersion = "output.krew.sigs.k8s.io/v1"
+
+// typeMeta identifies the schema of a printed object.
+type typeMeta struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+func newTypeMeta(kind string) typeMeta {
+	return typeMeta{APIVersion: outputAPIVersion, Kind: kind}
+}
+
+// installedPluginObject describes an installed plugin, built from its receipt.
+type installedPluginObject struct {
+	typeMeta
+	Name        string `json:"name"`
+	Index       string `json:"index"`
+	Version     string `json:"version"`
+	InstalledAt string `json:"installedAt,omitempty"`
+	// Deprecated and ReplacedBy are taken from the manifest in the index, if
+	// it could be loaded.
+	Deprecated string `json:"deprecated,omitempty"`
+	ReplacedBy string `json:"replacedBy,omitempty"`
+}
+
+func newInstalledPluginObject(r index.Receipt) installedPluginObject {
+	o := installedPluginObject{
+		typeMeta: newTypeMeta("InstalledPlugin"),
+		Name:     r.Name,
+		Index:    indexOf(r),
+		Version:  r.Spec.Version,
+	}
+	if !r.CreationTimestamp.IsZero() {
+		o.InstalledAt = r.CreationTimestamp.UTC().Format(time.RFC3339)
+	}
+	return o
+}
+
+type installedPluginList struct {
+	typeMeta
+	Items []installedPluginObject `json:"items"`
+}
+
+func newInstalledPluginList(receipts []index.Receipt) installedPluginList {
+	items := make([]installedPluginObject, 0, len(receipts))
+	for _, r := range receipts {
+		items = append(items, newInstalledPluginObject(r))
+	}
+	sort.Slice(items, func(a, b int) bool {
+		return displayPluginName(items[a].Name, items[a].Index) < displayPluginName(items[b].Name, items[b].Index)
+	})
+	return installedPluginList{typeMeta: newTypeMeta("InstalledPluginList"), Items: items}
+}
+
+func (l installedPluginList) names() []string {
+	out := make([]string, 0, len(l.Items))
+	for _, p := range l.Items {
+		out = append(out, displayPluginName(p.Name, p.Index))
+	}
+	return out
+}
+
+func (l installedPluginList) tableColumns(wide bool) []string {
+	if wide {
+		return []string{"PLUGIN", "VERSION", "INDEX", "INSTALLED"}
+	}
+	return []string{"PLUGIN", "VERSION"}
+}
+
+func (l installedPluginList) tableRows(wide bool) [][]string {
+	rows := make([][]string, 0, len(l.Items))
+	for _, p := range l.Items {
+		row := []string{displayPluginName(p.Name, p.Index), p.Version}
+		if wide {
+			row = append(row, p.Index, p.InstalledAt)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// pluginObject describes a plugin available in an index.
+type pluginObject struct {
+	typeMeta
+	Name             string `json:"name"`
+	Index            string `json:"index"`
+	Version          string `json:"version"`
+	ShortDescription string `json:"shortDescription"`
+	Description      string `json:"description,omitempty"`
+	Homepage         string `json:"homepage,omitempty"`
+	Caveats          string `json:"caveats,omitempty"`
+	Deprecated       string `json:"deprecated,omitempty"`
+	ReplacedBy       string `json:"replacedBy,omitempty"`
+	Installed        bool   `json:"installed"`
+	Available        bool   `json:"available"`
+
+	// Platforms and Installation are only set by "kubectl krew info".
+	Platforms    []platformObject    `json:"platforms,omitempty"`
+	Installation *installationObject `json:"installation,omitempty"`
+}
+
+// platformObject describes an installation candidate of a plugin.
+type platformObject struct {
+	Selector string `json:"selector"`
+	URI      string `json:"uri"`
+	Sha256   string `json:"sha256"`
+	Bin      string `json:"bin"`
+	// Matches is true if this platform is selected for the os/arch that the
+	// plugin information was requested for.
+	Matches bool `json:"matches"`
+}
+
+// installationObject describes the installed copy of a plugin, built from its
+// receipt and the files in its installation directory.
+type installationObject struct {
+	Version     string   `json:"version"`
+	InstalledAt string   `json:"installedAt,omitempty"`
+	BinPath     string   `json:"binPath"`
+	Files       []string `json:"files"`
+}
+
+// newPluginObject builds a pluginObject. The available field tells whether the
+// plugin offers an installation for the current platform.
+func newPluginObject(p index.Plugin, indexName string, installed, available bool) pluginObject {
+	return pluginObject{
+		typeMeta:         newTypeMeta("Plugin"),
+		Name:             p.Name,
+		Index:            indexName,
+		Version:          p.Spec.Version,
+		ShortDescription: p.Spec.ShortDescription,
+		Description:      p.Spec.Description,
+		Homepage:         p.Spec.Homepage,
+		Caveats:          p.Spec.Caveats,
+		Deprecated:       p.Spec.Deprecated,
+		ReplacedBy:       p.Spec.ReplacedBy,
+		Installed:        installed,
+		Available:        available,
+	}
+}
+
+func (p pluginObject) names() []string {
+	return []string{displayPluginName(p.Name, p.Index)}
+}
+
+type pluginList struct {
+	typeMeta
+	Items []pluginObject `json:"items"`
+}
+
+// newPluginList builds a pluginList that keeps the order of the given items.
+func newPluginList(items []pluginObject) pluginList {
+	if items == nil {
+		items = []pluginObject{}
+	}
+	return pluginList{typeMeta: newTypeMeta("PluginList"), Items: items}
+}
+
+func (l pluginList) names() []string {
+	out := make([]string, 0, len(l.Items))
+	for _, p := range l.Items {
+		out = append(out, displayPluginName(p.Name, p.Index))
+	}
+	return out
+}
+
+func (l pluginList) tableColumns(wide bool) []string {
+	if wide {
+		return []string{"NAME", "DESCRIPTION", "INSTALLED", "VERSION", "INDEX"}
+	}
+	return []string{"NAME", "DESCRIPTION", "INSTALLED"}
+}
+
+func (l pluginList) tableRows(wide bool) [][]string {
+	rows := make([][]string, 0, len(l.Items))
+	for _, p := range l.Items {
+		var status string
+		if p.Installed {
+			status = "yes"
+		} else if p.Available {
+			status = "no"
+		} else {
+			status = "unavailable on " + runtime.GOOS
+		}
+		description := p.ShortDescription
+		if p.Deprecated != "" || p.ReplacedBy != "" {
+			description = "(deprecated) " + description
+		}
+		row := []string{displayPluginName(p.Name, p.Index), limitString(description, 50), status}
+		if wide {
+			row = append(row, p.Version, p.Index)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// indexObject describes a configured plugin index.
+type indexObject struct {
+	typeMeta
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Ref      string `json:"ref,omitempty"`
+	Type     string `json:"type"`
+	Priority int    `json:"priority"`
+	// Status is omitted if the status of the local copy is unknown.
+	Status *indexStatusObject `json:"status,omitempty"`
+}
+
+// indexStatusObject describes the freshness and health of the local copy of
+// an index.
+type indexStatusObject struct {
+	LastUpdate      string `json:"lastUpdate,omitempty"`
+	Revision        string `json:"revision,omitempty"`
+	Plugins         int    `json:"plugins"`
+	FailedManifests int    `json:"failedManifests"`
+	Dirty           bool   `json:"dirty"`
+	Diverged        bool   `json:"diverged"`
+
+	lastUpdate time.Time
+}
+
+func newIndexObject(idx indexoperations.Index, status *indexoperations.Status) indexObject {
+	o := indexObject{
+		typeMeta: newTypeMeta("Index"),
+		Name:     idx.Name,
+		URL:      idx.URL,
+		Ref:      idx.Ref,
+		Type:     idx.Type,
+		Priority: idx.Priority,
+	}
+	if status != nil {
+		o.Status = &indexStatusObject{
+			Revision:        status.Revision,
+			Plugins:         status.Plugins,
+			FailedManifests: status.FailedManifests,
+			Dirty:           status.Dirty,
+			Diverged:        status.Diverged,
+			lastUpdate:      status.LastUpdate,
+		}
+		if !status.LastUpdate.IsZero() {
+			o.Status.LastUpdate = status.LastUpdate.UTC().Format(time.RFC3339)
+		}
+	}
+	return o
+}
+
+// tableColumns returns the values of the status columns of an index table.
+func (s *indexStatusObject) tableColumns() []string {
+	if s == nil {
+		return []string{"-", "-", "-", "-", "-"}
+	}
+	updated := "-"
+	if !s.lastUpdate.IsZero() {
+		updated = duration.HumanDuration(time.Since(s.lastUpdate)) + " ago"
+	}
+	var state []string
+	if s.Dirty {
+		state = append(state, "dirty")
+	}
+	if s.Diverged {
+		state = append(state, "diverged")
+	}
+	if len(state) == 0 {
+		state = append(state, "clean")
+	}
+	return []string{updated, shortRevision(s.Revision), strconv.Itoa(s.Plugins),
+		strconv.Itoa(s.FailedManifests), strings.Join(state, ",")}
+}
+
+type indexList struct {
+	typeMeta
+	Items []indexObject `json:"items"`
+}
+
+// newIndexList builds the list of indexes. Indexes without an entry in
+// statuses are printed without status.
+func newIndexList(indexes []indexoperations.Index, statuses map[string]indexoperations.Status) indexList {
+	items := make([]indexObject, 0, len(indexes))
+	for _, idx := range indexes {
+		var status *indexoperations.Status
+		if s, ok := statuses[idx.Name]; ok {
+			status = &s
+		}
+		items = append(items, newIndexObject(idx, status))
+	}
+	return indexList{typeMeta: newTypeMeta("IndexList"), Items: items}
+}
+
+func (l indexList) names() []string {
+	out := make([]string, 0, len(l.Items))
+	for _, idx := range l.Items {
+		out = append(out, idx.Name)
+	}
+	return out
+}
+
+func (l indexList) tableColumns(wide bool) []string {
+	columns := []string{"INDEX", "URL", "REF", "PRIORITY", "UPDATED", "REVISION", "PLUGINS", "FAILED", "STATE"}
+	if wide {
+		columns = append(columns, "TYPE")
+	}
+	return columns
+}
+
+func (l indexList) tableRows(wide bool) [][]string {
+	rows := make([][]string, 0, len(l.Items))
+	for _, idx := range l.Items {
+		ref := idx.Ref
+		if ref == "" {
+			ref = "-"
+		}
+		row := []string{idx.Name, idx.URL, ref, strconv.Itoa(idx.Priority)}
+		row = append(row, idx.Status.tableColumns()...)
+		if wide {
+			row = append(row, idx.Type)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/index/indexoperations"
+	"sigs.k8s.io/krew/internal/installation"
+	"sigs.k8s.io/krew/internal/installation/semver"
+	"sigs.k8s.io/krew/pkg/index"
+)
+
+// outdatedPluginObject describes an installed plugin compared to the version
+// available in its source index.
+type outdatedPluginObject struct {
+	typeMeta
+	Name             string `json:"name"`
+	Index            string `json:"index"`
+	InstalledVersion string `json:"installedVersion"`
+	AvailableVersion string `json:"availableVersion,omitempty"`
+	// Pinned is true if the plugin is never upgraded by "kubectl krew upgrade",
+	// which is the case for plugins installed from a manifest file.
+	Pinned   bool `json:"pinned"`
+	Outdated bool `json:"outdated"`
+}
+
+type outdatedPluginList struct {
+	typeMeta
+	Items []outdatedPluginObject `json:"items"`
+}
+
+// names returns plain plugin names, so that the output can be passed to the
+// "upgrade" command which does not accept INDEX/PLUGIN names.
+func (l outdatedPluginList) names() []string {
+	out := make([]string, 0, len(l.Items))
+	for _, p := range l.Items {
+		out = append(out, p.Name)
+	}
+	return out
+}
+
+func (l outdatedPluginList) tableColumns(bool) []string {
+	return []string{"PLUGIN", "INSTALLED", "AVAILABLE", "INDEX", "PINNED"}
+}
+
+func (l outdatedPluginList) tableRows(bool) [][]string {
+	rows := make([][]string, 0, len(l.Items))
+	for _, p := range l.Items {
+		available := p.AvailableVersion
+		if available == "" {
+			available = "-"
+		}
+		rows = append(rows, []string{displayPluginName(p.Name, p.Index), p.InstalledVersion, available, p.Index, strconv.FormatBool(p.Pinned)})
+	}
+	return rows
+}
+
+func init() {
+	var (
+		output   *string
+		all      *bool
+		exitCode *bool
+	)
+
+	// outdatedCmd represents the outdated command
+	outdatedCmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "List installed plugins that have newer versions available",
+		Long: `List installed plugins that have a newer version in their plugin index.
+
+This command compares the version of every installed plugin with the version in
+the local copy of the index it was installed from. Run "kubectl krew update"
+first to fetch the latest plugin versions.
+
+Plugins installed from a manifest file are pinned: they are never upgraded
+and are only shown with --all.
+
+Examples:
+  To list plugins that can be upgraded:
+    kubectl krew outdated
+
+  To fail a CI job if any plugin can be upgraded:
+    kubectl krew outdated --exit-code
+
+  To upgrade all outdated plugins:
+    kubectl krew upgrade $(kubectl krew outdated -o name)`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := parseOutputFormat(*output)
+			if err != nil {
+				return err
+			}
+			receipts, err := installation.GetInstalledPluginReceipts(paths.InstallReceiptsPath())
+			if err != nil {
+				return errors.Wrap(err, "failed to find all installed versions")
+			}
+			list, err := findOutdatedPlugins(paths, receipts, *all)
+			if err != nil {
+				return err
+			}
+			if len(list.Items) > 0 || !format.isHumanReadable() {
+				if err := printObject(os.Stdout, format, list); err != nil {
+					return err
+				}
+			}
+
+			var n int
+			for _, p := range list.Items {
+				if p.Outdated {
+					n++
+				}
+			}
+			if *exitCode && n > 0 {
+				return errors.Errorf("%d installed plugin(s) have newer versions available", n)
+			}
+			return nil
+		},
+		PreRunE: checkIndex,
+	}
+
+	output = addOutputFlag(outdatedCmd)
+	all = outdatedCmd.Flags().Bool("all", false, "Show all installed plugins, including up-to-date and pinned ones")
+	exitCode = outdatedCmd.Flags().Bool("exit-code", false, "Exit with a non-zero status if any plugin has a newer version available")
+	rootCmd.AddCommand(outdatedCmd)
+}
+
+// findOutdatedPlugins compares the installed version of each receipt with the
+// version in its source index. Unless all is set, only plugins with a newer
+// version available are returned.
+func findOutdatedPlugins(p environment.Paths, receipts []index.Receipt, all bool) (outdatedPluginList, error) {
+	list := outdatedPluginList{typeMeta: newTypeMeta("OutdatedPluginList"), Items: []outdatedPluginObject{}}
+	for _, r := range receipts {
+		o := outdatedPluginObject{
+			typeMeta:         newTypeMeta("OutdatedPlugin"),
+			Name:             r.Name,
+			Index:            indexOf(r),
+			InstalledVersion: r.Spec.Version,
+		}
+		if o.Index == detachedIndexName {
+			o.Pinned = true
+			if all {
+				list.Items = append(list.Items, o)
+			}
+			continue
+		}
+
+		plugin, err := indexoperations.LoadPlugin(p, o.Index, r.Name)
+		if os.IsNotExist(err) {
+			klog.Warningf("plugin %q does not exist in the plugin index %q", r.Name, o.Index)
+			if all {
+				list.Items = append(list.Items, o)
+			}
+			continue
+		} else if err != nil {
+			return list, errors.Wrapf(err, "failed to load the plugin manifest for plugin %s", displayPluginName(r.Name, o.Index))
+		}
+		o.AvailableVersion = plugin.Spec.Version
+
+		curv, err := semver.Parse(o.InstalledVersion)
+		if err != nil {
+			return list, errors.Wrapf(err, "failed to parse installed version (%q) of plugin %q", o.InstalledVersion, r.Name)
+		}
+		newv, err := semver.Parse(o.AvailableVersion)
+		if err != nil {
+			return list, errors.Wrapf(err, "failed to parse available version (%q) of plugin %q", o.AvailableVersion, r.Name)
+		}
+		o.Outdated = semver.Less(curv, newv)
+		if o.Outdated || all {
+			list.Items = append(list.Items, o)
+		}
+	}
+	sort.Slice(list.Items, func(a, b int) bool {
+		return displayPluginName(list.Items[a].Name, list.Items[a].Index) < displayPluginName(list.Items[b].Name, list.Items[b].Index)
+	})
+	return list, nil
+}
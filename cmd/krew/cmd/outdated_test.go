@@ -0,0 +1,90 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/constants"
+	"sigs.k8s.io/krew/pkg/index"
+)
+
+func Test_findOutdatedPlugins(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+
+	writeManifest := func(indexName, name, version string) {
+		tmpDir.WriteYAML(filepath.Join("index", indexName, "plugins", name+constants.ManifestExtension),
+			testutil.NewPlugin().WithName(name).WithVersion(version).V())
+	}
+	writeManifest(constants.DefaultIndexName, "foo", "v1.1.0")
+	writeManifest(constants.DefaultIndexName, "bar", "v2.0.0")
+	writeManifest("custom", "baz", "v0.2.0")
+
+	newReceipt := func(name, version, indexName string) index.Receipt {
+		return testutil.NewReceipt().
+			WithPlugin(testutil.NewPlugin().WithName(name).WithVersion(version).V()).
+			WithStatus(index.ReceiptStatus{Source: index.SourceIndex{Name: indexName}}).V()
+	}
+	receipts := []index.Receipt{
+		newReceipt("foo", "v1.0.0", constants.DefaultIndexName),
+		newReceipt("bar", "v2.0.0", constants.DefaultIndexName),
+		newReceipt("baz", "v0.1.0", "custom"),
+		newReceipt("qux", "v1.0.0", detachedIndexName),
+		newReceipt("gone", "v1.0.0", constants.DefaultIndexName),
+	}
+
+	got, err := findOutdatedPlugins(p, receipts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"custom/baz", "foo"}, displayNames(got)); diff != "" {
+		t.Errorf("outdated plugins mismatch: %s", diff)
+	}
+	for _, o := range got.Items {
+		if !o.Outdated || o.Pinned {
+			t.Errorf("expected %q to be outdated and not pinned: %+v", o.Name, o)
+		}
+	}
+
+	got, err = findOutdatedPlugins(p, receipts, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"bar", "custom/baz", "detached/qux", "foo", "gone"}, displayNames(got)); diff != "" {
+		t.Errorf("all plugins mismatch: %s", diff)
+	}
+	for _, o := range got.Items {
+		if o.Name == "qux" && !o.Pinned {
+			t.Errorf("expected plugin installed from manifest to be pinned: %+v", o)
+		}
+		if o.Name == "bar" && (o.Outdated || o.AvailableVersion != "v2.0.0") {
+			t.Errorf("expected plugin bar to be up-to-date: %+v", o)
+		}
+	}
+}
+
+func displayNames(l outdatedPluginList) []string {
+	var out []string
+	for _, o := range l.Items {
+		out = append(out, displayPluginName(o.Name, o.Index))
+	}
+	return out
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// Supported values for the --output flag. The template formats take their
+// argument after a "=" sign, e.g. "jsonpath={.items[*].name}".
+const (
+	outputTable      = ""
+	outputWide       = "wide"
+	outputJSON       = "json"
+	outputYAML       = "yaml"
+	outputName       = "name"
+	outputGoTemplate = "go-template"
+	outputJSONPath   = "jsonpath"
+)
+
+const outputFlagUsage = "Output format. One of: json|yaml|name|wide|go-template=TEMPLATE|jsonpath=EXPRESSION"
+
+// outputFormat is a parsed value of the --output flag.
+type outputFormat struct {
+	kind     string
+	template string
+}
+
+// tablePrinter is implemented by objects that can be printed as a table.
+type tablePrinter interface {
+	tableColumns(wide bool) []string
+	tableRows(wide bool) [][]string
+}
+
+// namePrinter is implemented by objects that can be printed with -o name.
+type namePrinter interface {
+	names() []string
+}
+
+// addOutputFlag registers the --output/-o flag on the command.
+func addOutputFlag(cmd *cobra.Command) *string {
+	return cmd.Flags().StringP("output", "o", "", outputFlagUsage)
+}
+
+// parseOutputFormat parses the value of the --output flag.
+func parseOutputFormat(s string) (outputFormat, error) {
+	kind, tmpl := s, ""
+	if i := strings.Index(s, "="); i >= 0 {
+		kind, tmpl = s[:i], s[i+1:]
+	}
+	switch kind {
+	case outputTable, outputWide, outputJSON, outputYAML, outputName:
+		if tmpl != "" {
+			return outputFormat{}, errors.Errorf("output format %q does not take an argument", kind)
+		}
+	case outputGoTemplate, outputJSONPath:
+		if tmpl == "" {
+			return outputFormat{}, errors.Errorf("output format %q requires a template, e.g. -o %s=TEMPLATE", kind, kind)
+		}
+	default:
+		return outputFormat{}, errors.Errorf("unsupported output format %q", s)
+	}
+	return outputFormat{kind: kind, template: tmpl}, nil
+}
+
+// isHumanReadable returns true if the output format is meant for humans
+// rather than programs.
+func (f outputFormat) isHumanReadable() bool {
+	return f.kind == outputTable || f.kind == outputWide
+}
+
+// printObject prints obj in the given output format. Structured formats
+// (json, yaml and templates) use the json field names of obj.
+func printObject(out io.Writer, f outputFormat, obj interface{}) error {
+	switch f.kind {
+	case outputJSON:
+		b, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to encode output as json")
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	case outputYAML:
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode output as yaml")
+		}
+		_, err = out.Write(b)
+		return err
+	case outputName:
+		np, ok := obj.(namePrinter)
+		if !ok {
+			return errors.Errorf("output format %q is not supported by this command", f.kind)
+		}
+		for _, n := range np.names() {
+			fmt.Fprintln(out, n)
+		}
+		return nil
+	case outputGoTemplate:
+		t, err := template.New("output").Parse(f.template)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse go-template")
+		}
+		data, err := toGeneric(obj)
+		if err != nil {
+			return err
+		}
+		return errors.Wrap(t.Execute(out, data), "failed to execute go-template")
+	case outputJSONPath:
+		jp := jsonpath.New("output")
+		if err := jp.Parse(relaxedJSONPath(f.template)); err != nil {
+			return errors.Wrap(err, "failed to parse jsonpath expression")
+		}
+		data, err := toGeneric(obj)
+		if err != nil {
+			return err
+		}
+		return errors.Wrap(jp.Execute(out, data), "failed to execute jsonpath expression")
+	default:
+		tp, ok := obj.(tablePrinter)
+		if !ok {
+			return errors.Errorf("output format %q is not supported by this command", f.kind)
+		}
+		wide := f.kind == outputWide
+		return printTable(out, tp.tableColumns(wide), tp.tableRows(wide))
+	}
+}
+
+// toGeneric converts obj into maps and slices keyed by the json field names,
+// so that templates can refer to fields the same way as in json output.
+func toGeneric(obj interface{}) (interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode output")
+	}
+	var out interface{}
+	return out, errors.Wrap(json.Unmarshal(b, &out), "failed to decode output")
+}
+
+// relaxedJSONPath wraps a jsonpath expression in braces if they are omitted,
+// so that both "{.items[*].name}" and ".items[*].name" are accepted.
+func relaxedJSONPath(s string) string {
+	if strings.HasPrefix(s, "{") {
+		return s
+	}
+	return "{" + s + "}"
+}
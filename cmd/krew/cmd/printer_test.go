@@ -0,0 +1,186 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/krew/internal/index/indexoperations"
+)
+
+func Test_parseOutputFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    outputFormat
+		wantErr bool
+	}{
+		{in: "", want: outputFormat{kind: outputTable}},
+		{in: "wide", want: outputFormat{kind: outputWide}},
+		{in: "json", want: outputFormat{kind: outputJSON}},
+		{in: "yaml", want: outputFormat{kind: outputYAML}},
+		{in: "name", want: outputFormat{kind: outputName}},
+		{in: "go-template={{.kind}}", want: outputFormat{kind: outputGoTemplate, template: "{{.kind}}"}},
+		{in: "jsonpath={.items[*].name}", want: outputFormat{kind: outputJSONPath, template: "{.items[*].name}"}},
+		{in: "jsonpath=", wantErr: true},
+		{in: "go-template", wantErr: true},
+		{in: "json=foo", wantErr: true},
+		{in: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseOutputFormat(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOutputFormat(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseOutputFormat(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_indexStatusObject_tableColumns(t *testing.T) {
+	s := &indexStatusObject{Plugins: 2, Dirty: true, Diverged: true, lastUpdate: time.Now().Add(-3 * time.Hour)}
+	want := []string{"3h ago", "-", "2", "0", "dirty,diverged"}
+	if diff := cmp.Diff(want, s.tableColumns()); diff != "" {
+		t.Errorf("tableColumns() mismatch: %s", diff)
+	}
+}
+
+func Test_printObject(t *testing.T) {
+	obj := newIndexList([]indexoperations.Index{
+		{Name: "default", URL: "https://example.com/default.git", Type: "git", Priority: 100},
+		{Name: "foo", URL: "https://example.com/foo.git", Ref: "v1.0", Type: "git"},
+	}, map[string]indexoperations.Status{
+		"default": {Revision: "0123456789abcdef", Plugins: 3, FailedManifests: 1, Dirty: true},
+	})
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "",
+			want: "INDEX    URL                              REF   PRIORITY  UPDATED  REVISION      PLUGINS  FAILED  STATE\n" +
+				"default  https://example.com/default.git  -     100       -        0123456789ab  3        1       dirty\n" +
+				"foo      https://example.com/foo.git      v1.0  0         -        -             -        -       -\n",
+		},
+		{
+			format: "name",
+			want:   "default\nfoo\n",
+		},
+		{
+			format: "jsonpath={.items[*].url}",
+			want:   "https://example.com/default.git https://example.com/foo.git",
+		},
+		{
+			format: "jsonpath=.kind",
+			want:   "IndexList",
+		},
+		{
+			format: `go-template={{range .items}}{{.name}}={{.url}};{{end}}`,
+			want:   "default=https://example.com/default.git;foo=https://example.com/foo.git;",
+		},
+		{
+			format: "yaml",
+			want: `apiVersion: output.krew.sigs.k8s.io/v1
+items:
+- apiVersion: output.krew.sigs.k8s.io/v1
+  kind: Index
+  name: default
+  priority: 100
+  status:
+    dirty: true
+    diverged: false
+    failedManifests: 1
+    plugins: 3
+    revision: 0123456789abcdef
+  type: git
+  url: https://example.com/default.git
+- apiVersion: output.krew.sigs.k8s.io/v1
+  kind: Index
+  name: foo
+  priority: 0
+  ref: v1.0
+  type: git
+  url: https://example.com/foo.git
+kind: IndexList
+`,
+		},
+		{
+			format: "json",
+			want: `{
+  "apiVersion": "output.krew.sigs.k8s.io/v1",
+  "kind": "IndexList",
+  "items": [
+    {
+      "apiVersion": "output.krew.sigs.k8s.io/v1",
+      "kind": "Index",
+      "name": "default",
+      "url": "https://example.com/default.git",
+      "type": "git",
+      "priority": 100,
+      "status": {
+        "revision": "0123456789abcdef",
+        "plugins": 3,
+        "failedManifests": 1,
+        "dirty": true,
+        "diverged": false
+      }
+    },
+    {
+      "apiVersion": "output.krew.sigs.k8s.io/v1",
+      "kind": "Index",
+      "name": "foo",
+      "url": "https://example.com/foo.git",
+      "ref": "v1.0",
+      "type": "git",
+      "priority": 0
+    }
+  ]
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			f, err := parseOutputFormat(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := printObject(&buf, f, obj); err != nil {
+				t.Fatalf("printObject() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("printObject() output mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_printObject_unsupported(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printObject(&buf, outputFormat{kind: outputName}, struct{}{}); err == nil {
+		t.Error("expected error when printing names of an object without names")
+	}
+	if err := printObject(&buf, outputFormat{kind: outputTable}, struct{}{}); err == nil {
+		t.Error("expected error when printing an object without table support as a table")
+	}
+}
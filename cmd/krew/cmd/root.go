@@ -31,11 +31,13 @@ import (
 
 	"sigs.k8s.io/krew/cmd/krew/cmd/internal"
 	"sigs.k8s.io/krew/internal/environment"
-	"sigs.k8s.io/krew/internal/gitutil"
+	"sigs.k8s.io/krew/internal/index/indexbackend"
+	"sigs.k8s.io/krew/internal/indexconfigmigration"
 	"sigs.k8s.io/krew/internal/indexmigration"
 	"sigs.k8s.io/krew/internal/installation"
 	"sigs.k8s.io/krew/internal/installation/receipt"
 	"sigs.k8s.io/krew/internal/installation/semver"
+	"sigs.k8s.io/krew/internal/network"
 	"sigs.k8s.io/krew/internal/receiptsmigration"
 	"sigs.k8s.io/krew/internal/version"
 	"sigs.k8s.io/krew/pkg/constants"
@@ -55,6 +57,8 @@ var (
 	// An empty string indicates that the API request was skipped or
 	// has not completed.
 	latestTag = ""
+
+	offlineFlag *bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -98,6 +102,8 @@ func init() {
 		os.Exit(1)
 	}
 
+	offlineFlag = rootCmd.PersistentFlags().Bool("offline", false,
+		"Do not access the network, fail operations that need it; plugin archives must be given with --archive (or set "+network.OfflineEnv+"=1)")
 	paths = environment.MustGetKrewPaths()
 
 	// Cobra doesn't have a way to specify a two word command (ie. "kubectl krew"), so set a custom usage template
@@ -108,6 +114,10 @@ func init() {
 }
 
 func preRun(cmd *cobra.Command, _ []string) error {
+	if *offlineFlag {
+		network.SetOffline(true)
+	}
+
 	// check must be done before ensureDirs, to detect krew's self-installation
 	if !internal.IsBinDirInPATH(paths) {
 		internal.PrintWarning(os.Stderr, internal.SetupInstructions()+"\n\n")
@@ -123,6 +133,7 @@ func preRun(cmd *cobra.Command, _ []string) error {
 
 	go func() {
 		if _, disabled := os.LookupEnv("KREW_NO_UPGRADE_CHECK"); disabled ||
+			network.Offline() ||
 			isDevelopmentBuild() || // no upgrade check for dev builds
 			upgradeCheckRate < rand.Float64() { // only do the upgrade check randomly
 			klog.V(1).Infof("skipping upgrade check")
@@ -159,6 +170,16 @@ func preRun(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
+	isMigrated, err = indexconfigmigration.Done(paths)
+	if err != nil {
+		return errors.Wrap(err, "failed to check if index configuration migration is complete")
+	}
+	if !isMigrated {
+		if err := indexconfigmigration.Migrate(paths); err != nil {
+			return errors.Wrap(err, "index configuration migration failed")
+		}
+	}
+
 	if installation.IsWindows() {
 		klog.V(4).Infof("detected windows, will check for old krew installations to clean up")
 		err := cleanupStaleKrewInstallations()
@@ -208,8 +229,8 @@ func cleanupStaleKrewInstallations() error {
 }
 
 func checkIndex(_ *cobra.Command, _ []string) error {
-	if ok, err := gitutil.IsGitCloned(paths.IndexPath(constants.DefaultIndexName)); err != nil {
-		return errors.Wrap(err, "failed to check local index git repository")
+	if ok, err := indexbackend.Exists(paths.IndexPath(constants.DefaultIndexName)); err != nil {
+		return errors.Wrap(err, "failed to check local index")
 	} else if !ok {
 		return errors.New(`krew local plugin index is not initialized (run "kubectl krew update")`)
 	}
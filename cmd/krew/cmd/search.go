@@ -16,7 +16,7 @@ package cmd
 
 import (
 	"os"
-	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -25,10 +25,26 @@ import (
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/krew/internal/index/indexoperations"
-	"sigs.k8s.io/krew/internal/index/indexscanner"
 	"sigs.k8s.io/krew/internal/installation"
 )
 
+// Weights of the fields that a search keyword can match. A plugin is ranked by
+// the sum of the weights of its matching fields.
+const (
+	searchScoreExactName        = 1000
+	searchScoreNamePrefix       = 500
+	searchScoreFuzzyName        = 100
+	searchScoreShortDescription = 50
+	searchScoreDescription      = 10
+)
+
+var (
+	searchOutput      *string
+	searchInstalled   *bool
+	searchAvailableOn *string
+	searchIndex       *string
+)
+
 // searchCmd represents the search command
 var searchCmd = &cobra.Command{
 	Use:   "search",
@@ -36,23 +52,55 @@ var searchCmd = &cobra.Command{
 	Long: `List kubectl plugins available on krew and search among them.
 If no arguments are provided, all plugins will be listed.
 
+Keywords are fuzzy matched against plugin names and searched in the plugin
+descriptions. Results are ranked so that exact name matches come first,
+followed by name prefix matches, fuzzy name matches and description matches.
+
 Examples:
   To list all plugins:
     kubectl krew search
 
-  To fuzzy search plugins with a keyword:
-    kubectl krew search KEYWORD`,
+  To search plugins with a keyword:
+    kubectl krew search KEYWORD
+
+  To search installed plugins from a custom index:
+    kubectl krew search --installed --index=INDEX KEYWORD
+
+  To list plugins that can be installed on a different platform:
+    kubectl krew search --available-on=linux/arm64
+
+  To print the names of all plugins using a jsonpath expression:
+    kubectl krew search -o jsonpath='{.items[*].name}'`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := parseOutputFormat(*searchOutput)
+		if err != nil {
+			return err
+		}
+		// plugins are marked as available if they can be installed on the
+		// platform selected with --available-on, or on this one
+		platform := installation.OSArch()
+		if *searchAvailableOn != "" {
+			if platform, err = installation.ParseOSArch(*searchAvailableOn); err != nil {
+				return errors.Wrap(err, "invalid --available-on value")
+			}
+		}
+
 		indexes, err := indexoperations.ListIndexes(paths)
 		if err != nil {
 			return errors.Wrap(err, "failed to list indexes")
 		}
+		if *searchIndex != "" {
+			indexes = filterIndexes(indexes, *searchIndex)
+			if len(indexes) == 0 {
+				return errors.Errorf("index %q does not exist", *searchIndex)
+			}
+		}
 
 		klog.V(3).Infof("found %d indexes", len(indexes))
 
 		var plugins []pluginEntry
 		for _, idx := range indexes {
-			ps, err := indexscanner.LoadPluginListFromFS(paths.IndexPluginsPath(idx.Name))
+			ps, err := indexoperations.LoadPluginList(paths, idx.Name)
 			if err != nil {
 				return errors.Wrapf(err, "failed to load the list of plugins from the index %q", idx.Name)
 			}
@@ -61,14 +109,6 @@ Examples:
 			}
 		}
 
-		pluginCanonicalNames := make([]string, len(plugins))
-		pluginCanonicalNameMap := make(map[string]pluginEntry, len(plugins))
-		for i, p := range plugins {
-			cn := canonicalName(p.p, p.indexName)
-			pluginCanonicalNames[i] = cn
-			pluginCanonicalNameMap[cn] = p
-		}
-
 		installed := make(map[string]bool)
 		receipts, err := installation.GetInstalledPluginReceipts(paths.InstallReceiptsPath())
 		if err != nil {
@@ -79,42 +119,110 @@ Examples:
 			installed[cn] = true
 		}
 
-		var searchResults []string
-		if len(args) > 0 {
-			matches := fuzzy.Find(strings.Join(args, ""), pluginCanonicalNames)
-			for _, m := range matches {
-				searchResults = append(searchResults, m.Str)
+		var results []searchResult
+		for _, p := range plugins {
+			cn := canonicalName(p.p, p.indexName)
+			if *searchInstalled && !installed[cn] {
+				continue
+			}
+			if *searchAvailableOn != "" {
+				if _, ok, err := installation.GetMatchingPlatformFor(p.p.Spec.Platforms, platform); err != nil {
+					return errors.Wrapf(err, "failed to get the matching platform for plugin %s", cn)
+				} else if !ok {
+					continue
+				}
+			}
+			var score int
+			if len(args) > 0 {
+				if score = searchScore(p, args); score == 0 {
+					continue
+				}
 			}
-		} else {
-			searchResults = pluginCanonicalNames
+			results = append(results, searchResult{entry: p, score: score})
+		}
+		sortSearchResults(results)
+
+		var items []pluginObject
+		for _, r := range results {
+			v := r.entry
+			_, ok, err := installation.GetMatchingPlatformFor(v.p.Spec.Platforms, platform)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get the matching platform for plugin %s", canonicalName(v.p, v.indexName))
+			}
+			items = append(items, newPluginObject(v.p, v.indexName, installed[canonicalName(v.p, v.indexName)], ok))
 		}
 
 		// No plugins found
-		if len(searchResults) == 0 {
+		if len(items) == 0 && format.isHumanReadable() {
 			return nil
 		}
+		return printObject(os.Stdout, format, newPluginList(items))
+	},
+	PreRunE: checkIndex,
+}
 
-		var rows [][]string
-		cols := []string{"NAME", "DESCRIPTION", "INSTALLED"}
-		for _, canonicalName := range searchResults {
-			v := pluginCanonicalNameMap[canonicalName]
-			var status string
-			if installed[canonicalName] {
-				status = "yes"
-			} else if _, ok, err := installation.GetMatchingPlatform(v.p.Spec.Platforms); err != nil {
-				return errors.Wrapf(err, "failed to get the matching platform for plugin %s", canonicalName)
-			} else if ok {
-				status = "no"
-			} else {
-				status = "unavailable on " + runtime.GOOS
-			}
+type searchResult struct {
+	entry pluginEntry
+	score int
+}
+
+// searchScore returns the ranking score of a plugin for the given search
+// keywords. Plugins that do not match the keywords have a score of zero.
+func searchScore(p pluginEntry, keywords []string) int {
+	query := strings.ToLower(strings.Join(keywords, ""))
+	name := strings.ToLower(p.p.Name)
+	fullName := strings.ToLower(displayName(p.p, p.indexName))
+
+	var score int
+	switch {
+	case name == query || fullName == query:
+		score += searchScoreExactName
+	case strings.HasPrefix(name, query) || strings.HasPrefix(fullName, query):
+		score += searchScoreNamePrefix
+	case len(fuzzy.Find(query, []string{fullName})) > 0:
+		score += searchScoreFuzzyName
+	}
+	if containsAllKeywords(p.p.Spec.ShortDescription, keywords) {
+		score += searchScoreShortDescription
+	}
+	if containsAllKeywords(p.p.Spec.Description, keywords) {
+		score += searchScoreDescription
+	}
+	return score
+}
 
-			rows = append(rows, []string{displayName(v.p, v.indexName), limitString(v.p.Spec.ShortDescription, 50), status})
+// containsAllKeywords checks if every keyword occurs in s, ignoring case.
+func containsAllKeywords(s string, keywords []string) bool {
+	s = strings.ToLower(s)
+	for _, k := range keywords {
+		if !strings.Contains(s, strings.ToLower(k)) {
+			return false
 		}
-		rows = sortByFirstColumn(rows)
-		return printTable(os.Stdout, cols, rows)
-	},
-	PreRunE: checkIndex,
+	}
+	return s != "" && len(keywords) > 0
+}
+
+// sortSearchResults orders the results by descending score. Results with the
+// same score are ordered by their display name.
+func sortSearchResults(results []searchResult) {
+	sort.SliceStable(results, func(a, b int) bool {
+		if results[a].score != results[b].score {
+			return results[a].score > results[b].score
+		}
+		return displayName(results[a].entry.p, results[a].entry.indexName) <
+			displayName(results[b].entry.p, results[b].entry.indexName)
+	})
+}
+
+// filterIndexes returns the indexes with the given name.
+func filterIndexes(indexes []indexoperations.Index, name string) []indexoperations.Index {
+	var out []indexoperations.Index
+	for _, idx := range indexes {
+		if idx.Name == name {
+			out = append(out, idx)
+		}
+	}
+	return out
 }
 
 func limitString(s string, length int) string {
@@ -125,5 +233,9 @@ func limitString(s string, length int) string {
 }
 
 func init() {
+	searchOutput = addOutputFlag(searchCmd)
+	searchInstalled = searchCmd.Flags().Bool("installed", false, "Only show installed plugins")
+	searchAvailableOn = searchCmd.Flags().String("available-on", "", "Only show plugins that can be installed on the given os/arch (e.g. linux/arm64 or linux/amd64/musl)")
+	searchIndex = searchCmd.Flags().String("index", "", "Only show plugins from the given index")
 	rootCmd.AddCommand(searchCmd)
 }
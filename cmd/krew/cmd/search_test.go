@@ -0,0 +1,76 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+func Test_searchScore(t *testing.T) {
+	newEntry := func(name, indexName, short, desc string) pluginEntry {
+		p := testutil.NewPlugin().WithName(name).V()
+		p.Spec.ShortDescription = short
+		p.Spec.Description = desc
+		return pluginEntry{p, indexName}
+	}
+	entries := []pluginEntry{
+		newEntry("view-secret", constants.DefaultIndexName, "Decode Kubernetes secrets", ""),
+		newEntry("secret", "foo", "Manage secrets", ""),
+		newEntry("secretive", constants.DefaultIndexName, "Does something", ""),
+		newEntry("ctx", constants.DefaultIndexName, "Switch between contexts", "Handles secret rotation too."),
+		newEntry("ns", constants.DefaultIndexName, "Switch between namespaces", ""),
+	}
+
+	var results []searchResult
+	for _, e := range entries {
+		if score := searchScore(e, []string{"secret"}); score > 0 {
+			results = append(results, searchResult{entry: e, score: score})
+		}
+	}
+	sortSearchResults(results)
+
+	var got []string
+	for _, r := range results {
+		got = append(got, displayName(r.entry.p, r.entry.indexName))
+	}
+	want := []string{"foo/secret", "secretive", "view-secret", "ctx"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("search results mismatch: %s", diff)
+	}
+}
+
+func Test_containsAllKeywords(t *testing.T) {
+	tests := []struct {
+		s        string
+		keywords []string
+		want     bool
+	}{
+		{s: "Show an RBAC access matrix", keywords: []string{"rbac"}, want: true},
+		{s: "Show an RBAC access matrix", keywords: []string{"access", "MATRIX"}, want: true},
+		{s: "Show an RBAC access matrix", keywords: []string{"access", "pod"}, want: false},
+		{s: "", keywords: []string{"access"}, want: false},
+		{s: "Show an RBAC access matrix", keywords: nil, want: false},
+	}
+	for _, tt := range tests {
+		if got := containsAllKeywords(tt.s, tt.keywords); got != tt.want {
+			t.Errorf("containsAllKeywords(%q, %v) = %v, want %v", tt.s, tt.keywords, got, tt.want)
+		}
+	}
+}
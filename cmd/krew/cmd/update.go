@@ -16,36 +16,102 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
-	"sigs.k8s.io/krew/internal/gitutil"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
 	"sigs.k8s.io/krew/internal/index/indexoperations"
-	"sigs.k8s.io/krew/internal/index/indexscanner"
 	"sigs.k8s.io/krew/internal/installation"
 	"sigs.k8s.io/krew/pkg/constants"
 	"sigs.k8s.io/krew/pkg/index"
 )
 
+const (
+	// defaultUpdateWorkers is the number of indexes updated at the same time.
+	defaultUpdateWorkers = 4
+	// defaultIndexUpdateTimeout is the time after which the update of a
+	// single index is aborted.
+	defaultIndexUpdateTimeout = 5 * time.Minute
+)
+
+var (
+	updateWorkers *int
+	updateTimeout *time.Duration
+)
+
 // updateCmd represents the update command
 var updateCmd = &cobra.Command{
-	Use:   "update",
+	Use:   "update [INDEX...]",
 	Short: "Update the local copy of the plugin index",
 	Long: `Update the local copy of the plugin index.
 
 This command synchronizes the local copy of the plugin manifests with the
-plugin index from the internet.
+plugin index from the internet. All configured indexes are updated unless
+index names are given.
+
+Indexes are updated concurrently, and the update of an index is aborted if it
+takes longer than --timeout. A summary of the updated indexes is printed when
+the updates are done.
 
 Remarks:
   You don't need to run this command: Running "krew update" or "krew upgrade"
   will silently run this command.`,
-	RunE: ensureIndexes,
+	Example: `  kubectl krew update
+  kubectl krew update default corp
+  kubectl krew update --workers=8 --timeout=30s`,
+	RunE: func(_ *cobra.Command, args []string) error {
+		if *updateWorkers < 1 {
+			return errors.New("--workers must be at least 1")
+		}
+		if err := ensureDefaultIndexIfNoneExist(); err != nil {
+			return err
+		}
+		indexes, err := selectIndexes(args)
+		if err != nil {
+			return err
+		}
+		results := updateIndexes(indexes, *updateWorkers, *updateTimeout)
+		if err := printTable(os.Stderr, []string{"INDEX", "OLD REVISION", "NEW REVISION", "NEW PLUGINS", "ERROR"},
+			updateSummaryRows(results)); err != nil {
+			return err
+		}
+		return reportUpdateResults(results)
+	},
+}
+
+// indexUpdateResult describes the outcome of updating a single index.
+type indexUpdateResult struct {
+	index       indexoperations.Index
+	oldRevision string
+	newRevision string
+	preUpdate   []pluginEntry
+	postUpdate  []pluginEntry
+	err         error
+}
+
+// newPlugins returns the number of plugins that the update added to the index.
+func (r indexUpdateResult) newPlugins() int {
+	old := make(map[string]bool, len(r.preUpdate))
+	for _, p := range r.preUpdate {
+		old[p.p.Name] = true
+	}
+	var n int
+	for _, p := range r.postUpdate {
+		if !old[p.p.Name] {
+			n++
+		}
+	}
+	return n
 }
 
 func showFormattedPluginsInfo(out io.Writer, header string, plugins []string) {
@@ -109,7 +175,7 @@ func showUpdatedPlugins(out io.Writer, preUpdate, postUpdate []pluginEntry, inst
 func loadPlugins(indexes []indexoperations.Index) []pluginEntry {
 	var out []pluginEntry
 	for _, idx := range indexes {
-		list, err := indexscanner.LoadPluginListFromFS(paths.IndexPluginsPath(idx.Name))
+		list, err := indexoperations.LoadPluginList(paths, idx.Name)
 		if err != nil {
 			klog.V(1).Infof("WARNING: failed to load plugin list from %q: %v", idx.Name, err)
 			continue
@@ -129,6 +195,31 @@ func ensureIndexes(_ *cobra.Command, _ []string) error {
 	return ensureIndexesUpdated()
 }
 
+// selectIndexes returns the configured indexes with the given names, or all
+// indexes if no names are given.
+func selectIndexes(names []string) ([]indexoperations.Index, error) {
+	indexes, err := indexoperations.ListIndexes(paths)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list indexes")
+	}
+	if len(names) == 0 {
+		return indexes, nil
+	}
+	byName := make(map[string]indexoperations.Index, len(indexes))
+	for _, idx := range indexes {
+		byName[idx.Name] = idx
+	}
+	var out []indexoperations.Index
+	for _, name := range names {
+		idx, ok := byName[name]
+		if !ok {
+			return nil, errors.Errorf("index %q does not exist", name)
+		}
+		out = append(out, idx)
+	}
+	return out, nil
+}
+
 // ensureDefaultIndexIfNoneExist adds the default index automatically
 // (and informs the user about it) if no plugin index exists for krew.
 func ensureDefaultIndexIfNoneExist() error {
@@ -144,45 +235,100 @@ func ensureDefaultIndexIfNoneExist() error {
 	klog.V(3).Infof("No index found, add default index.")
 	defaultIndex := index.DefaultIndex()
 	fmt.Fprintf(os.Stderr, "Adding \"default\" plugin index from %s.\n", defaultIndex)
-	return errors.Wrap(indexoperations.AddIndex(paths, constants.DefaultIndexName, defaultIndex),
+	return errors.Wrap(indexoperations.AddIndex(paths, constants.DefaultIndexName, defaultIndex, "",
+		indexconfig.DefaultPriority(constants.DefaultIndexName), nil),
 		"failed to add default plugin index in absence of no indexes")
 }
 
-// ensureIndexesUpdated iterates over all indexes and updates them
-// and prints new plugins and upgrades available for installed plugins.
+// ensureIndexesUpdated updates all indexes and prints new plugins and
+// upgrades available for installed plugins.
 func ensureIndexesUpdated() error {
 	indexes, err := indexoperations.ListIndexes(paths)
 	if err != nil {
 		return errors.Wrap(err, "failed to list indexes")
 	}
+	results := updateIndexes(indexes, defaultUpdateWorkers, defaultIndexUpdateTimeout)
+	for _, r := range results {
+		if r.err != nil {
+			continue
+		}
+		if isDefaultIndex(r.index.Name) {
+			fmt.Fprintln(os.Stderr, "Updated the local copy of plugin index.")
+		} else {
+			fmt.Fprintf(os.Stderr, "Updated the local copy of plugin index %q.\n", r.index.Name)
+		}
+	}
+	return reportUpdateResults(results)
+}
 
-	// collect list of existing plugins
-	preUpdatePlugins := loadPlugins(indexes)
+// updateIndexes updates the given indexes with at most workers updates
+// running at the same time. The update of each index is aborted after
+// timeout. The results are in the order of indexes.
+func updateIndexes(indexes []indexoperations.Index, workers int, timeout time.Duration) []indexUpdateResult {
+	results := make([]indexUpdateResult, len(indexes))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(indexes); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = updateIndex(indexes[i], timeout)
+			}
+		}()
+	}
+	for i := range indexes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func updateIndex(idx indexoperations.Index, timeout time.Duration) indexUpdateResult {
+	r := indexUpdateResult{index: idx}
+	var err error
+	if r.oldRevision, err = indexoperations.IndexRevision(paths, idx.Name); err != nil {
+		klog.V(1).Infof("WARNING: failed to get the revision of index %q: %v", idx.Name, err)
+	}
+	r.preUpdate = loadPlugins([]indexoperations.Index{idx})
+
+	klog.V(1).Infof("Updating the local copy of plugin index (%s)", paths.IndexPath(idx.Name))
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	r.err = indexoperations.UpdateIndex(ctx, paths, idx.Name)
+	if ctx.Err() == context.DeadlineExceeded {
+		r.err = errors.Errorf("update timed out after %s", timeout)
+	}
+	if r.err != nil {
+		klog.Warningf("failed to update index %q: %v", idx.Name, r.err)
+	}
+
+	if r.newRevision, err = indexoperations.IndexRevision(paths, idx.Name); err != nil {
+		klog.V(1).Infof("WARNING: failed to get the revision of index %q: %v", idx.Name, err)
+	}
+	r.postUpdate = loadPlugins([]indexoperations.Index{idx})
+	return r
+}
 
+// reportUpdateResults prints new plugins and upgrades available for installed
+// plugins, and returns an error listing the indexes that failed to update.
+func reportUpdateResults(results []indexUpdateResult) error {
 	var failed []string
 	var returnErr error
-	for _, idx := range indexes {
-		indexPath := paths.IndexPath(idx.Name)
-		klog.V(1).Infof("Updating the local copy of plugin index (%s)", indexPath)
-		if err := gitutil.EnsureUpdated(idx.URL, indexPath); err != nil {
-			klog.Warningf("failed to update index %q: %v", idx.Name, err)
-			failed = append(failed, idx.Name)
+	var preUpdatePlugins, postUpdatePlugins []pluginEntry
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r.index.Name)
 			if returnErr == nil {
-				returnErr = err
+				returnErr = r.err
 			}
-			continue
-		}
-
-		if isDefaultIndex(idx.Name) {
-			fmt.Fprintln(os.Stderr, "Updated the local copy of plugin index.")
-		} else {
-			fmt.Fprintf(os.Stderr, "Updated the local copy of plugin index %q.\n", idx.Name)
 		}
+		preUpdatePlugins = append(preUpdatePlugins, r.preUpdate...)
+		postUpdatePlugins = append(postUpdatePlugins, r.postUpdate...)
 	}
 
 	if len(preUpdatePlugins) != 0 {
-		postUpdatePlugins := loadPlugins(indexes)
-
 		receipts, err := installation.GetInstalledPluginReceipts(paths.InstallReceiptsPath())
 		if err != nil {
 			return errors.Wrap(err, "failed to load installed plugins list after update")
@@ -196,6 +342,40 @@ func ensureIndexesUpdated() error {
 	return errors.Wrapf(returnErr, "failed to update the following indexes: %s\n", strings.Join(failed, ", "))
 }
 
+// updateSummaryRows returns a table row for the result of each index update.
+func updateSummaryRows(results []indexUpdateResult) [][]string {
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		errMsg := "-"
+		if r.err != nil {
+			errMsg = strings.SplitN(r.err.Error(), "\n", 2)[0]
+		}
+		rows = append(rows, []string{
+			r.index.Name,
+			shortRevision(r.oldRevision),
+			shortRevision(r.newRevision),
+			strconv.Itoa(r.newPlugins()),
+			errMsg,
+		})
+	}
+	return rows
+}
+
+// shortRevision abbreviates long revisions such as commit hashes.
+func shortRevision(rev string) string {
+	const maxLen = 12
+	switch {
+	case rev == "":
+		return "-"
+	case len(rev) > maxLen:
+		return rev[:maxLen]
+	default:
+		return rev
+	}
+}
+
 func init() {
+	updateWorkers = updateCmd.Flags().Int("workers", defaultUpdateWorkers, "Number of indexes to update at the same time")
+	updateTimeout = updateCmd.Flags().Duration("timeout", defaultIndexUpdateTimeout, "Time after which the update of a single index is aborted")
 	rootCmd.AddCommand(updateCmd)
 }
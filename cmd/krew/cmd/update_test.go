@@ -0,0 +1,56 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/krew/internal/index/indexoperations"
+	"sigs.k8s.io/krew/internal/testutil"
+)
+
+func Test_updateSummaryRows(t *testing.T) {
+	foo := pluginEntry{p: testutil.NewPlugin().WithName("foo").V(), indexName: "default"}
+	bar := pluginEntry{p: testutil.NewPlugin().WithName("bar").V(), indexName: "default"}
+	results := []indexUpdateResult{
+		{
+			index:       indexoperations.Index{Name: "default"},
+			oldRevision: "0123456789abcdef0123456789abcdef01234567",
+			newRevision: "fedcba9876543210fedcba9876543210fedcba98",
+			preUpdate:   []pluginEntry{foo},
+			postUpdate:  []pluginEntry{foo, bar},
+		},
+		{
+			index: indexoperations.Index{Name: "local"},
+		},
+		{
+			index:       indexoperations.Index{Name: "broken"},
+			oldRevision: `"etag"`,
+			newRevision: `"etag"`,
+			err:         errors.New("update timed out after 1s\nmore details"),
+		},
+	}
+	want := [][]string{
+		{"default", "0123456789ab", "fedcba987654", "1", "-"},
+		{"local", "-", "-", "0", "-"},
+		{"broken", `"etag"`, `"etag"`, "0", "update timed out after 1s"},
+	}
+	if diff := cmp.Diff(want, updateSummaryRows(results)); diff != "" {
+		t.Errorf("updateSummaryRows() mismatch: %s", diff)
+	}
+}
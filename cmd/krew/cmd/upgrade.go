@@ -23,16 +23,18 @@ import (
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/krew/cmd/krew/cmd/internal"
-	"sigs.k8s.io/krew/internal/index/indexscanner"
+	"sigs.k8s.io/krew/internal/index/indexoperations"
 	"sigs.k8s.io/krew/internal/index/validation"
 	"sigs.k8s.io/krew/internal/installation"
 	"sigs.k8s.io/krew/internal/installation/receipt"
+	"sigs.k8s.io/krew/internal/network"
 	"sigs.k8s.io/krew/internal/pathutil"
 	"sigs.k8s.io/krew/pkg/constants"
+	"sigs.k8s.io/krew/pkg/index"
 )
 
 func init() {
-	var noUpdateIndex *bool
+	var noUpdateIndex, replace *bool
 
 	// upgradeCmd represents the upgrade command
 	var upgradeCmd = &cobra.Command{
@@ -42,7 +44,11 @@ func init() {
 This will reinstall all plugins that have a newer version in the local index.
 Use "kubectl krew update" to renew the index.
 To only upgrade single plugins provide them as arguments:
-kubectl krew upgrade foo bar"`,
+kubectl krew upgrade foo bar"
+
+Plugins that are deprecated in their index are upgraded with a warning. If a
+plugin was replaced by another plugin (e.g. because it was renamed), use
+--replace to install the replacement and uninstall the plugin instead.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var ignoreUpgraded bool
 			var skipErrors bool
@@ -78,12 +84,12 @@ kubectl krew upgrade foo bar"`,
 			var nErrors int
 			for _, name := range pluginNames {
 				indexName, pluginName := pathutil.CanonicalPluginName(name)
-				if indexName == "detached" {
+				if indexName == detachedIndexName {
 					klog.Warningf("Skipping upgrade for %q because it was installed via manifest\n", pluginName)
 					continue
 				}
 
-				plugin, err := indexscanner.LoadPluginByName(paths.IndexPluginsPath(indexName), pluginName)
+				plugin, err := indexoperations.LoadPlugin(paths, indexName, pluginName)
 				if err != nil {
 					if !os.IsNotExist(err) {
 						return errors.Wrapf(err, "failed to load the plugin manifest for plugin %s", name)
@@ -93,6 +99,24 @@ kubectl krew upgrade foo bar"`,
 				}
 
 				pluginDisplayName := displayName(plugin, indexName)
+				if err == nil && isDeprecated(plugin.Spec) {
+					if plugin.Spec.ReplacedBy != "" && *replace {
+						if err := replacePlugin(plugin, indexName); err != nil {
+							nErrors++
+							if skipErrors {
+								fmt.Fprintf(os.Stderr, "WARNING: failed to replace plugin %q, skipping (error: %v)\n", pluginDisplayName, err)
+								continue
+							}
+							return errors.Wrapf(err, "failed to replace plugin %q", pluginDisplayName)
+						}
+						continue
+					}
+					fmt.Fprintf(os.Stderr, "WARNING: %s\n", deprecationNotice(plugin.Name, indexName, plugin.Spec.Deprecated, plugin.Spec.ReplacedBy))
+					if plugin.Spec.ReplacedBy != "" {
+						fmt.Fprintf(os.Stderr, "Run \"kubectl krew upgrade --replace %s\" to install %q instead.\n",
+							plugin.Name, displayPluginName(plugin.Spec.ReplacedBy, indexName))
+					}
+				}
 				if err == nil {
 					fmt.Fprintf(os.Stderr, "Upgrading plugin: %s\n", pluginDisplayName)
 					err = installation.Upgrade(paths, plugin, indexName)
@@ -124,10 +148,45 @@ kubectl krew upgrade foo bar"`,
 				klog.V(4).Infof("--no-update-index specified, skipping updating local copy of plugin index")
 				return nil
 			}
+			if network.Offline() {
+				klog.V(1).Infof("offline mode, skipping updating local copy of plugin index")
+				return nil
+			}
 			return ensureIndexes(cmd, args)
 		},
 	}
 
 	noUpdateIndex = upgradeCmd.Flags().Bool("no-update-index", false, "(Experimental) do not update local copy of plugin index before upgrading")
+	replace = upgradeCmd.Flags().Bool("replace", false, "Replace plugins that were replaced by another plugin in their index")
 	rootCmd.AddCommand(upgradeCmd)
 }
+
+// replacePlugin installs the plugin that replaces a deprecated plugin from the
+// same index, and uninstalls the deprecated plugin.
+func replacePlugin(plugin index.Plugin, indexName string) error {
+	replacement, err := indexoperations.LoadPlugin(paths, indexName, plugin.Spec.ReplacedBy)
+	if os.IsNotExist(err) {
+		return errors.Errorf("replacement %q does not exist in the plugin index", plugin.Spec.ReplacedBy)
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to load the plugin manifest for plugin %s", plugin.Spec.ReplacedBy)
+	}
+
+	replacementDisplayName := displayName(replacement, indexName)
+	fmt.Fprintf(os.Stderr, "Replacing plugin %s with %s\n", displayName(plugin, indexName), replacementDisplayName)
+	err = installation.Install(paths, replacement, indexName, installation.InstallOpts{})
+	if err == installation.ErrIsAlreadyInstalled {
+		klog.V(1).Infof("Plugin %q is already installed", replacement.Name)
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to install plugin %q", replacementDisplayName)
+	} else {
+		fmt.Fprintf(os.Stderr, "Installed plugin: %s\n", replacementDisplayName)
+		if indexName == constants.DefaultIndexName {
+			internal.PrintSecurityNotice(replacement.Name)
+		}
+	}
+	if err := installation.Uninstall(paths, plugin.Name); err != nil {
+		return errors.Wrapf(err, "failed to uninstall plugin %q", displayName(plugin, indexName))
+	}
+	fmt.Fprintf(os.Stderr, "Uninstalled plugin: %s\n", plugin.Name)
+	return nil
+}
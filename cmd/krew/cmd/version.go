@@ -38,8 +38,11 @@ Remarks:
   - BasePath is the root directory for krew installation.
   - IndexPath is the directory that stores the local copy of the index git repository.
   - InstallPath is the directory for plugin installations.
-  - BinPath is the directory for the symbolic links to the installed plugin executables.`,
+  - BinPath is the directory for the symbolic links to the installed plugin executables.
+  - DetectedPlatform describes the os/arch that plugins are installed for.
+  - DetectedLabels are the labels that platform selectors of plugins are matched against.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		env := installation.OSArch()
 		conf := [][]string{
 			{"GitTag", version.GitTag()},
 			{"GitCommit", version.GitCommit()},
@@ -48,7 +51,8 @@ Remarks:
 			{"IndexPath", paths.IndexPath(constants.DefaultIndexName)},
 			{"InstallPath", paths.InstallPath()},
 			{"BinPath", paths.BinPath()},
-			{"DetectedPlatform", installation.OSArch().String()},
+			{"DetectedPlatform", env.String()},
+			{"DetectedLabels", env.Labels().String()},
 		}
 		return printTable(os.Stdout, []string{"OPTION", "VALUE"}, conf)
 	},
@@ -27,7 +27,6 @@ import (
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/krew/internal/environment"
@@ -83,6 +82,14 @@ func validateManifestFile(path string) error {
 	}
 	klog.Infof("structural validation OK")
 
+	if p.Spec.ReplacedBy != "" {
+		replacement := filepath.Join(filepath.Dir(path), p.Spec.ReplacedBy+constants.ManifestExtension)
+		if _, err := os.Stat(replacement); err != nil {
+			return errors.Wrapf(err, "replacement plugin %q not found in the index", p.Spec.ReplacedBy)
+		}
+		klog.Infof("replacement plugin %q exists", p.Spec.ReplacedBy)
+	}
+
 	// make sure each platform matches a supported platform
 	for i, p := range p.Spec.Platforms {
 		if env := findAnyMatchingPlatform(p.Selector); env.OS == "" || env.Arch == "" {
@@ -151,6 +158,8 @@ func installPlatformSpec(manifestFile string, p index.Platform) error {
 		"KREW_ROOT=" + tmpDir,
 		"KREW_OS=" + env.OS,
 		"KREW_ARCH=" + env.Arch,
+		"KREW_LIBC=" + env.Libc,
+		"KREW_ARM=" + env.ArmVariant,
 	}
 	klog.V(2).Infof("installing plugin with: %+v", cmd.Env)
 	cmd.Env = append(cmd.Env, "PATH="+os.Getenv("PATH"))
@@ -221,24 +230,27 @@ func selectorMatchesOSArch(selector *metav1.LabelSelector, env installation.OSAr
 		klog.Warningf("Failed to convert label selector: %+v", selector)
 		return false
 	}
-	return sel.Matches(labels.Set{
-		"os":   env.OS,
-		"arch": env.Arch,
-	})
+	return sel.Matches(env.Labels())
 }
 
-// allPlatforms returns all <os,arch> pairs krew is supported on.
+// allPlatforms returns all <os,arch> pairs krew is supported on, with the
+// libc and ARM variants of Linux.
 func allPlatforms() []installation.OSArchPair {
 	// TODO(ahmetb) find a more authoritative source for this list
-	return []installation.OSArchPair{
+	platforms := []installation.OSArchPair{
 		{OS: "windows", Arch: "386"},
 		{OS: "windows", Arch: "amd64"},
-		{OS: "linux", Arch: "386"},
-		{OS: "linux", Arch: "amd64"},
-		{OS: "linux", Arch: "arm"},
-		{OS: "linux", Arch: "arm64"},
-		{OS: "darwin", Arch: "386"},
-		{OS: "darwin", Arch: "amd64"},
-		{OS: "darwin", Arch: "arm64"},
 	}
+	for _, libc := range []string{"glibc", "musl"} {
+		platforms = append(platforms,
+			installation.OSArchPair{OS: "linux", Arch: "386", Libc: libc},
+			installation.OSArchPair{OS: "linux", Arch: "amd64", Libc: libc},
+			installation.OSArchPair{OS: "linux", Arch: "arm", Libc: libc, ArmVariant: "v6"},
+			installation.OSArchPair{OS: "linux", Arch: "arm", Libc: libc, ArmVariant: "v7"},
+			installation.OSArchPair{OS: "linux", Arch: "arm64", Libc: libc})
+	}
+	return append(platforms,
+		installation.OSArchPair{OS: "darwin", Arch: "386"},
+		installation.OSArchPair{OS: "darwin", Arch: "amd64"},
+		installation.OSArchPair{OS: "darwin", Arch: "arm64"})
 }
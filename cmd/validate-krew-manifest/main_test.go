@@ -134,6 +134,22 @@ func Test_selectorMatchesOSArch(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "libc label - match",
+			args: args{
+				selector: &metav1.LabelSelector{MatchLabels: map[string]string{"os": "linux", "libc": "musl"}},
+				env:      installation.OSArchPair{OS: "linux", Arch: "amd64", Libc: "musl"},
+			},
+			want: true,
+		},
+		{
+			name: "libc label - no match",
+			args: args{
+				selector: &metav1.LabelSelector{MatchLabels: map[string]string{"os": "linux", "libc": "musl"}},
+				env:      installation.OSArchPair{OS: "linux", Arch: "amd64", Libc: "glibc"},
+			},
+			want: false,
+		},
 		{
 			name: "expression - no match",
 			args: args{
@@ -209,3 +225,25 @@ func Test_isOverlappingPlatformSelectors_overlap(t *testing.T) {
 		t.Fatal("expected overlap")
 	}
 }
+
+func Test_isOverlappingPlatformSelectors_libc(t *testing.T) {
+	glibc := testutil.NewPlatform().WithSelector(&metav1.LabelSelector{
+		MatchLabels: map[string]string{"os": "linux"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      "libc",
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   []string{"musl"},
+		}},
+	}).V()
+	musl := testutil.NewPlatform().WithSelector(&metav1.LabelSelector{
+		MatchLabels: map[string]string{"os": "linux", "libc": "musl"},
+	}).V()
+	if err := isOverlappingPlatformSelectors([]index.Platform{glibc, musl}); err != nil {
+		t.Fatalf("expected no overlap: %+v", err)
+	}
+
+	linux := testutil.NewPlatform().WithOS("linux").V()
+	if err := isOverlappingPlatformSelectors([]index.Platform{linux, musl}); err == nil {
+		t.Fatal("expected overlap")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrationtest
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+func TestKrewBundle(t *testing.T) {
+	skipShort(t)
+
+	online := NewTest(t)
+	online.WithDefaultIndex()
+	list := online.TempDir().Write("plugins.txt", []byte("# plugins for the bastion\n"+validPlugin+"\n\n")).Path("plugins.txt")
+	bundleFile := online.TempDir().Path("bundle.tar")
+	online.Krew("bundle", "create", "-f", list, "-o", bundleFile).RunOrFail()
+
+	offline := NewTest(t)
+	offline.WithEnv("KREW_OFFLINE", 1).Krew("bundle", "install", bundleFile).RunOrFail()
+	offline.AssertExecutableInPATH("kubectl-" + validPlugin)
+	offline.AssertPluginFromIndex(validPlugin, constants.DefaultIndexName)
+}
+
+func TestKrewBundle_WrongPlatform(t *testing.T) {
+	skipShort(t)
+
+	online := NewTest(t)
+	online.WithDefaultIndex()
+	list := online.TempDir().Write("plugins.txt", []byte(validPlugin)).Path("plugins.txt")
+	bundleFile := online.TempDir().Path("bundle.tar")
+	online.Krew("bundle", "create", "-f", list, "--platform", "darwin/amd64", "-o", bundleFile).RunOrFail()
+
+	offline := NewTest(t)
+	out, err := offline.WithEnv("KREW_OS", "linux").WithEnv("KREW_ARCH", "amd64").
+		Krew("bundle", "install", bundleFile).Run()
+	if err == nil {
+		t.Fatal("expected installing a bundle for another platform to fail")
+	}
+	if !strings.Contains(string(out), "darwin/amd64") {
+		t.Errorf("expected error about the bundle platform, got: %s", out)
+	}
+	offline.AssertExecutableNotInPATH("kubectl-" + validPlugin)
+}
+
+func TestKrewBundle_Libc(t *testing.T) {
+	skipShort(t)
+
+	online := NewTest(t)
+	online.WithDefaultIndex()
+	list := online.TempDir().Write("plugins.txt", []byte(validPlugin)).Path("plugins.txt")
+	bundleFile := online.TempDir().Path("bundle.tar")
+	online.Krew("bundle", "create", "-f", list, "--platform", "linux/amd64/musl", "-o", bundleFile).RunOrFail()
+
+	glibc := NewTest(t).WithEnv("KREW_OS", "linux").WithEnv("KREW_ARCH", "amd64").WithEnv("KREW_LIBC", "glibc")
+	if _, err := glibc.Krew("bundle", "install", bundleFile).Run(); err == nil {
+		t.Fatal("expected installing a musl bundle on a glibc host to fail")
+	}
+	glibc.AssertExecutableNotInPATH("kubectl-" + validPlugin)
+
+	musl := NewTest(t).WithEnv("KREW_OS", "linux").WithEnv("KREW_ARCH", "amd64").WithEnv("KREW_LIBC", "musl")
+	musl.Krew("bundle", "install", bundleFile).RunOrFail()
+	musl.AssertExecutableInPATH("kubectl-" + validPlugin)
+}
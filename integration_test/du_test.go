@@ -0,0 +1,93 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrationtest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+func TestKrewDu(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex().Krew("install", validPlugin).RunOrFail()
+
+	out := string(test.Krew("du").RunOrFailOutput())
+	for _, want := range []string{"plugin", validPlugin, "index", constants.DefaultIndexName, "total"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected du output to contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestKrewDu_JSON(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex().Krew("install", validPlugin).RunOrFail()
+	test.Krew("search").RunOrFail()
+
+	var report struct {
+		Plugins []struct {
+			Name  string `json:"name"`
+			Bytes int64  `json:"bytes"`
+		} `json:"plugins"`
+		Indexes []struct {
+			Name  string `json:"name"`
+			Bytes int64  `json:"bytes"`
+		} `json:"indexes"`
+		CacheBytes int64 `json:"cacheBytes"`
+		TotalBytes int64 `json:"totalBytes"`
+	}
+	out := test.Krew("du", "-o", "json").RunOrFailOutput()
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("cannot parse du output as json: %v\n%s", err, out)
+	}
+
+	var found bool
+	for _, p := range report.Plugins {
+		if p.Name == validPlugin && p.Bytes > 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected plugin %q with non-zero size in report: %+v", validPlugin, report.Plugins)
+	}
+	if len(report.Indexes) != 1 || report.Indexes[0].Name != constants.DefaultIndexName {
+		t.Errorf("expected only the default index in report: %+v", report.Indexes)
+	}
+	if report.CacheBytes <= 0 {
+		t.Errorf("expected the plugin list cache in report, got %d bytes", report.CacheBytes)
+	}
+	if report.TotalBytes <= 0 {
+		t.Errorf("expected positive total size, got %d", report.TotalBytes)
+	}
+}
+
+func TestKrewDu_InvalidOutput(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	if _, err := test.Krew("du", "-o", "xml").Run(); err == nil {
+		t.Fatal("expected du with unsupported output format to fail")
+	}
+}
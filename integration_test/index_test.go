@@ -15,12 +15,20 @@
 package integrationtest
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/gitutil"
 	"sigs.k8s.io/krew/pkg/constants"
 )
 
@@ -48,6 +56,55 @@ func TestKrewIndexAdd(t *testing.T) {
 	}
 }
 
+func TestKrewIndexAdd_Ref(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex()
+	index := environment.NewPaths(test.Root()).IndexPath(constants.DefaultIndexName)
+	commit, err := gitutil.Exec(index, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := test.Krew("index", "add", "foo", index, "--ref", "does-not-exist").Run(); err == nil {
+		t.Fatal("expected index add with nonexistent ref to fail")
+	}
+	test.Krew("index", "add", "foo", index, "--ref", commit).RunOrFail()
+	test.Krew("update").RunOrFail()
+
+	out := string(test.Krew("index", "list", "-o", `jsonpath={.items[?(@.name=="foo")].ref}`).RunOrFailOutput())
+	if out != commit {
+		t.Errorf("expected index foo to be pinned to %q, got %q", commit, out)
+	}
+}
+
+func TestKrewIndexAdd_LocalDirectory(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex()
+	src := environment.NewPaths(test.Root()).IndexPluginsPath(constants.DefaultIndexName)
+	dir := filepath.Join(test.Root(), "monorepo")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("cp", "-r", src, filepath.Join(dir, "plugins")).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	test.Krew("index", "add", "local", "file://"+filepath.ToSlash(dir)).RunOrFail()
+	test.Krew("update").RunOrFail()
+
+	out := string(test.Krew("index", "list", "-o", `jsonpath={.items[?(@.name=="local")].type}`).RunOrFailOutput())
+	if out != "local" {
+		t.Errorf("expected index of type local, got %q", out)
+	}
+	test.Krew("install", "local/"+validPlugin).RunOrFail()
+	test.AssertExecutableInPATH("kubectl-" + validPlugin)
+}
+
 func TestKrewIndexAddUnsafe(t *testing.T) {
 	skipShort(t)
 	test := NewTest(t)
@@ -99,6 +156,71 @@ func TestKrewIndexList(t *testing.T) {
 	}
 }
 
+func TestKrewIndexList_OutputName(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+	out := test.Krew("index", "list", "-o", "name").RunOrFailOutput()
+	if diff := cmp.Diff([]string{constants.DefaultIndexName, "foo"}, lines(out)); diff != "" {
+		t.Fatalf("'index list -o name' output doesn't match:\n%s", diff)
+	}
+}
+
+func TestKrewIndexList_Status(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+	test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+
+	type status struct {
+		LastUpdate      string `json:"lastUpdate"`
+		Revision        string `json:"revision"`
+		Plugins         int    `json:"plugins"`
+		FailedManifests int    `json:"failedManifests"`
+		Dirty           bool   `json:"dirty"`
+		Diverged        bool   `json:"diverged"`
+	}
+	indexStatus := func() status {
+		t.Helper()
+		var list struct {
+			Items []struct {
+				Name   string  `json:"name"`
+				Status *status `json:"status"`
+			} `json:"items"`
+		}
+		out := test.Krew("index", "list", "-o", "json").RunOrFailOutput()
+		// skip errors about broken manifests logged to stderr
+		if i := bytes.IndexByte(out, '{'); i > 0 {
+			out = out[i:]
+		}
+		if err := json.Unmarshal(out, &list); err != nil {
+			t.Fatalf("cannot parse index list output as json: %v\n%s", err, out)
+		}
+		for _, idx := range list.Items {
+			if idx.Name == "foo" && idx.Status != nil {
+				return *idx.Status
+			}
+		}
+		t.Fatalf("index list has no status for index foo:\n%s", out)
+		return status{}
+	}
+
+	s := indexStatus()
+	if s.LastUpdate == "" || s.Revision == "" || s.Plugins == 0 || s.FailedManifests != 0 || s.Dirty || s.Diverged {
+		t.Errorf("unexpected status of a freshly added index: %+v", s)
+	}
+
+	pluginsDir := environment.NewPaths(test.Root()).IndexPluginsPath("foo")
+	if err := ioutil.WriteFile(filepath.Join(pluginsDir, "broken"+constants.ManifestExtension), []byte("apiVersion: ["), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s := indexStatus(); s.FailedManifests != 1 || !s.Dirty {
+		t.Errorf("expected a broken manifest to be counted and make the index dirty, got %+v", s)
+	}
+}
+
 func TestKrewIndexList_NoIndexes(t *testing.T) {
 	skipShort(t)
 
@@ -166,6 +288,43 @@ func TestKrewIndexRemoveFailsWhenPluginsInstalled(t *testing.T) {
 	test.Krew("index", "remove", "--force", "default").RunOrFail()
 }
 
+func TestKrewIndexRename(t *testing.T) {
+	skipShort(t)
+	test := NewTest(t)
+	test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+
+	test.Krew("install", "foo/"+validPlugin).RunOrFail()
+	test.Krew("index", "rename", "foo", "bar").RunOrFail()
+	test.AssertPluginFromIndex(validPlugin, "bar")
+	if out := string(test.Krew("index", "list", "-o", "name").RunOrFailOutput()); strings.Contains(out, "foo") || !strings.Contains(out, "bar") {
+		t.Errorf("expected index to be renamed, got:\n%s", out)
+	}
+	if _, err := test.Krew("index", "rename", "missing", "baz").Run(); err == nil {
+		t.Error("expected renaming a missing index to fail")
+	}
+	test.Krew("uninstall", validPlugin).RunOrFail()
+}
+
+func TestKrewIndexSetURL(t *testing.T) {
+	skipShort(t)
+	test := NewTest(t)
+	test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+
+	mirror := test.TempDir().Path("mirror")
+	defaultIndex := environment.NewPaths(test.Root()).IndexPath(constants.DefaultIndexName)
+	if _, err := gitutil.Exec("", "clone", "-q", defaultIndex, mirror); err != nil {
+		t.Fatal(err)
+	}
+	test.Krew("index", "set-url", "foo", mirror).RunOrFail()
+	url, err := gitutil.GetRemoteURL(environment.NewPaths(test.Root()).IndexPath("foo"))
+	if err != nil || url != mirror {
+		t.Errorf("expected remote %q, got %q, %v", mirror, url, err)
+	}
+	if out := string(test.Krew("index", "list").RunOrFailOutput()); !strings.Contains(out, mirror) {
+		t.Errorf("expected index list to show the new URL:\n%s", out)
+	}
+}
+
 func TestKrewIndexRemoveForce_nonExisting(t *testing.T) {
 	skipShort(t)
 	test := NewTest(t)
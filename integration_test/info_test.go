@@ -57,3 +57,42 @@ func TestKrewInfoCustomIndex(t *testing.T) {
 		t.Fatalf("info output doesn't have %q. output=%q", expected, out)
 	}
 }
+
+func TestKrewInfo_Installed(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+	test = test.WithDefaultIndex()
+
+	out := string(test.Krew("info", validPlugin).RunOrFailOutput())
+	if !strings.Contains(out, "INSTALLED: no") {
+		t.Fatalf("expected plugin not to be installed. output=%q", out)
+	}
+
+	test.Krew("install", validPlugin).RunOrFail()
+	out = string(test.Krew("info", validPlugin).RunOrFailOutput())
+	for _, expected := range []string{"INSTALLED: yes", "INSTALLED VERSION: ", "BIN PATH: ", "FILES:", "PLATFORMS:"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("info output doesn't have %q. output=%q", expected, out)
+		}
+	}
+}
+
+func TestKrewInfo_Platform(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+	test = test.WithDefaultIndex()
+
+	out := string(test.Krew("info", validPlugin, "--platform", "darwin/amd64", "-o", "jsonpath={.available}").RunOrFailOutput())
+	if out != "true" {
+		t.Errorf("expected plugin to be available on darwin/amd64, got %q", out)
+	}
+	out = string(test.Krew("info", validPlugin, "--platform", "windows/amd64", "-o", "jsonpath={.available}").RunOrFailOutput())
+	if out != "false" {
+		t.Errorf("expected plugin not to be available on windows/amd64, got %q", out)
+	}
+	if _, err := test.Krew("info", validPlugin, "--platform", "windows").Run(); err == nil {
+		t.Error("expected info with an invalid platform to fail")
+	}
+}
@@ -147,6 +147,30 @@ func TestKrewInstall_CustomIndex(t *testing.T) {
 	test.AssertExecutableNotInPATH("kubectl-" + validPlugin2)
 }
 
+func TestKrewInstall_IndexPriority(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+	test.Krew("install", validPlugin).RunOrFail()
+	test.AssertPluginFromIndex(validPlugin, constants.DefaultIndexName)
+	test.Krew("uninstall", validPlugin).RunOrFail()
+
+	test.Krew("index", "set-priority", "foo", "100").RunOrFail()
+	out, err := test.Krew("install", validPlugin).Run()
+	if err == nil {
+		t.Fatal("expected install of a plugin from indexes with the same priority to fail")
+	}
+	if want := "default/" + validPlugin + ", foo/" + validPlugin; !strings.Contains(string(out), want) {
+		t.Errorf("expected error to list candidates %q, got: %s", want, out)
+	}
+
+	test.Krew("index", "set-priority", "foo", "200").RunOrFail()
+	test.Krew("install", validPlugin).RunOrFail()
+	test.AssertPluginFromIndex(validPlugin, "foo")
+}
+
 func TestKrewInstallNoSecurityWarningForCustomIndex(t *testing.T) {
 	skipShort(t)
 
@@ -198,6 +222,145 @@ func TestKrewInstall_ManifestAndArchive(t *testing.T) {
 	test.AssertPluginFromIndex(fooPlugin, "detached")
 }
 
+func TestKrewInstall_Executables(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t).WithDefaultIndex()
+
+	manifest := func(name, executable string) string {
+		return test.TempDir().Write(name+constants.ManifestExtension, []byte(`apiVersion: krew.googlecontainertools.github.com/v1beta1
+kind: Plugin
+metadata:
+  name: `+name+`
+spec:
+  version: "v0.1.0"
+  shortDescription: A plugin with additional executables
+  platforms:
+  - uri: https://foo.bar/foo.tar.gz
+    sha256: 354bad230cdd0966fc8c919476c4e6c7f2078b04a6ff7dead6a811cdc101d31e
+    bin: foo.sh
+    executables:
+    - name: `+executable+`
+      bin: foo.sh
+    selector:
+      matchExpressions:
+      - key: os
+        operator: In
+        values: ["darwin", "linux"]
+`)).Path(name + constants.ManifestExtension)
+	}
+	archive := filepath.Join("testdata", fooPlugin+".tar.gz")
+
+	test.Krew("install", "--manifest", manifest(fooPlugin, "foo-extra"), "--archive", archive).RunOrFail()
+	test.AssertExecutableInPATH("kubectl-" + fooPlugin)
+	test.AssertExecutableInPATH("kubectl-foo_extra")
+
+	if _, err := test.Krew("install", "--manifest", manifest("bar", "foo-extra"), "--archive", archive).Run(); err == nil {
+		t.Error("expected install of a colliding executable to fail")
+	}
+	test.AssertExecutableNotInPATH("kubectl-bar")
+
+	test.Krew("uninstall", fooPlugin).RunOrFail()
+	test.AssertExecutableNotInPATH("kubectl-" + fooPlugin)
+	test.AssertExecutableNotInPATH("kubectl-foo_extra")
+}
+
+func TestKrewInstall_Completion(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t).WithDefaultIndex()
+
+	manifest := test.TempDir().Write(fooPlugin+constants.ManifestExtension, []byte(`apiVersion: krew.googlecontainertools.github.com/v1beta1
+kind: Plugin
+metadata:
+  name: foo
+spec:
+  version: "v0.1.0"
+  shortDescription: A plugin with shell completion
+  platforms:
+  - uri: https://foo.bar/foo.tar.gz
+    sha256: 354bad230cdd0966fc8c919476c4e6c7f2078b04a6ff7dead6a811cdc101d31e
+    bin: foo.sh
+    completion: foo.sh
+    selector:
+      matchExpressions:
+      - key: os
+        operator: In
+        values: ["darwin", "linux"]
+`)).Path(fooPlugin + constants.ManifestExtension)
+
+	test.Krew("install", "--manifest", manifest, "--archive", filepath.Join("testdata", fooPlugin+".tar.gz")).RunOrFail()
+	test.AssertExecutableInPATH("kubectl-" + fooPlugin)
+	test.AssertExecutableInPATH("kubectl_complete-" + fooPlugin)
+
+	test.Krew("uninstall", fooPlugin).RunOrFail()
+	test.AssertExecutableNotInPATH("kubectl_complete-" + fooPlugin)
+}
+
+func TestKrewInstall_LibcSelector(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t).WithDefaultIndex()
+
+	manifest := test.TempDir().Write(fooPlugin+constants.ManifestExtension, []byte(`apiVersion: krew.googlecontainertools.github.com/v1beta1
+kind: Plugin
+metadata:
+  name: foo
+spec:
+  version: "v0.1.0"
+  shortDescription: A plugin built for musl only
+  platforms:
+  - uri: https://foo.bar/foo.tar.gz
+    sha256: 354bad230cdd0966fc8c919476c4e6c7f2078b04a6ff7dead6a811cdc101d31e
+    bin: foo.sh
+    selector:
+      matchLabels:
+        os: linux
+        libc: musl
+`)).Path(fooPlugin + constants.ManifestExtension)
+	archive := filepath.Join("testdata", fooPlugin+".tar.gz")
+
+	if _, err := test.WithEnv("KREW_OS", "linux").WithEnv("KREW_LIBC", "glibc").
+		Krew("install", "--manifest", manifest, "--archive", archive).Run(); err == nil {
+		t.Fatal("expected install on glibc to fail")
+	}
+	test.WithEnv("KREW_LIBC", "musl").Krew("install", "--manifest", manifest, "--archive", archive).RunOrFail()
+	test.AssertExecutableInPATH("kubectl-" + fooPlugin)
+}
+
+func TestKrewInstall_V1beta1Manifest(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t).WithDefaultIndex()
+
+	manifest := test.TempDir().Write(fooPlugin+constants.ManifestExtension, []byte(`apiVersion: krew.googlecontainertools.github.com/v1beta1
+kind: Plugin
+metadata:
+  name: foo
+spec:
+  version: "v0.1.0"
+  shortDescription: A plugin with a v1beta1 manifest
+  deprecated: use bar instead
+  platforms:
+  - uri: https://foo.bar/foo.tar.gz
+    sha256: 354bad230cdd0966fc8c919476c4e6c7f2078b04a6ff7dead6a811cdc101d31e
+    bin: foo.sh
+    selector:
+      matchExpressions:
+      - key: os
+        operator: In
+        values: ["darwin", "linux"]
+`)).Path(fooPlugin + constants.ManifestExtension)
+
+	test.Krew("install", "--manifest", manifest, "--archive", filepath.Join("testdata", fooPlugin+".tar.gz")).RunOrFail()
+	test.AssertExecutableInPATH("kubectl-" + fooPlugin)
+
+	r := test.loadReceipt(environment.NewPaths(test.Root()).PluginInstallReceiptPath(fooPlugin))
+	if r.APIVersion != "krew.googlecontainertools.github.com/v1beta1" || r.Spec.Deprecated != "use bar instead" {
+		t.Errorf("expected receipt to keep the v1beta1 manifest, got apiVersion=%q deprecated=%q", r.APIVersion, r.Spec.Deprecated)
+	}
+}
+
 func TestKrewInstall_OnlyArchive(t *testing.T) {
 	skipShort(t)
 
@@ -15,6 +15,7 @@
 package integrationtest
 
 import (
+	"regexp"
 	"sort"
 	"strings"
 	"testing"
@@ -84,3 +85,27 @@ func TestKrewListSorted(t *testing.T) {
 		t.Fatalf("list output is not sorted: [%s]", strings.Join(out, ", "))
 	}
 }
+
+func TestKrewList_OutputFormats(t *testing.T) {
+	skipShort(t)
+	test := NewTest(t)
+
+	test = test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+	test.Krew("install", validPlugin).RunOrFail()
+	test.Krew("install", "foo/"+validPlugin2).RunOrFail()
+
+	want := []string{validPlugin, "foo/" + validPlugin2}
+	if diff := cmp.Diff(want, lines(test.Krew("list", "-o", "name").RunOrFailOutput())); diff != "" {
+		t.Fatalf("'list -o name' output doesn't match:\n%s", diff)
+	}
+
+	out := string(test.Krew("list", "-o", "wide").RunOrFailOutput())
+	if !regexp.MustCompile(`(?m)^foo/` + validPlugin2 + `\s+\S+\s+foo\s`).MatchString(out) {
+		t.Fatalf("expected index column in wide output:\n%s", out)
+	}
+
+	got := string(test.Krew("list", "-o", `go-template={{range .items}}{{.index}}/{{.name}} {{end}}`).RunOrFailOutput())
+	if wantOut := "default/" + validPlugin + " foo/" + validPlugin2 + " "; got != wantOut {
+		t.Fatalf("go-template output = %q, want %q", got, wantOut)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrationtest
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+func TestKrewMigrate(t *testing.T) {
+	skipShort(t)
+	test := NewTest(t)
+	test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+
+	if _, err := test.Krew("migrate", validPlugin, "--to-index", "foo").Run(); err == nil {
+		t.Error("expected migrating a plugin that is not installed to fail")
+	}
+	test.Krew("install", validPlugin).RunOrFail()
+	if _, err := test.Krew("migrate", validPlugin, "--to-index", "missing").Run(); err == nil {
+		t.Error("expected migrating to a missing index to fail")
+	}
+
+	test.Krew("migrate", validPlugin, "--to-index", "foo").RunOrFail()
+	test.AssertPluginFromIndex(validPlugin, "foo")
+	test.AssertExecutableInPATH("kubectl-" + validPlugin)
+
+	// a newer version in the target index is installed when migrating back
+	manifest := filepath.Join(environment.NewPaths(test.Root()).IndexPluginsPath(constants.DefaultIndexName), validPlugin+constants.ManifestExtension)
+	b, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = regexp.MustCompile(`(?m)^(\s*version:\s*).*$`).ReplaceAll(b, []byte("${1}v99.0.0"))
+	if err := ioutil.WriteFile(manifest, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	test.Krew("migrate", validPlugin, "--to-index", constants.DefaultIndexName, "--no-update-index").RunOrFail()
+	test.AssertPluginFromIndex(validPlugin, constants.DefaultIndexName)
+	test.AssertExecutableInPATH("kubectl-" + validPlugin)
+	r := test.loadReceipt(environment.NewPaths(test.Root()).PluginInstallReceiptPath(validPlugin))
+	if r.Spec.Version != "v99.0.0" {
+		t.Errorf("expected the plugin to be reinstalled at v99.0.0, got %s", r.Spec.Version)
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"sigs.k8s.io/krew/internal/environment"
 	"sigs.k8s.io/krew/pkg/constants"
 )
@@ -39,6 +41,27 @@ func TestKrewIndexAutoMigration(t *testing.T) {
 	}
 }
 
+func TestKrewIndexConfigAutoMigration(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+	configPath := environment.NewPaths(test.Root()).IndexConfigPath()
+	if err := os.Remove(configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// any command here should generate the index configuration
+	out := test.Krew("index", "list", "-o", "name").RunOrFailOutput()
+	if diff := cmp.Diff([]string{constants.DefaultIndexName, "foo"}, lines(out)); diff != "" {
+		t.Errorf("indexes after migration don't match:\n%s", diff)
+	}
+	if _, err := os.Stat(configPath); err != nil {
+		t.Errorf("index configuration should have been created: %v", err)
+	}
+}
+
 func TestKrewUnsupportedVersion(t *testing.T) {
 	skipShort(t)
 
@@ -0,0 +1,77 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrationtest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/gitutil"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+func TestKrewOffline_InstallFromIndexFails(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex()
+	out, err := test.Krew("install", "--offline", validPlugin).Run()
+	if err == nil {
+		t.Fatal("expected install that downloads a plugin to fail in offline mode")
+	}
+	if !strings.Contains(string(out), "needs network access") {
+		t.Errorf("expected error about network access, got: %s", out)
+	}
+	test.AssertExecutableNotInPATH("kubectl-" + validPlugin)
+}
+
+func TestKrewOffline_InstallFromArchive(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithEnv("KREW_OFFLINE", 1).Krew("install",
+		"--manifest", filepath.Join("testdata", fooPlugin+constants.ManifestExtension),
+		"--archive", filepath.Join("testdata", fooPlugin+".tar.gz")).
+		RunOrFail()
+	test.AssertExecutableInPATH("kubectl-" + fooPlugin)
+}
+
+func TestKrewOffline_NetworkCommandsFail(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex()
+	indexPath := environment.NewPaths(test.Root()).IndexPath(constants.DefaultIndexName)
+	if _, err := gitutil.Exec(indexPath, "remote", "set-url", "origin", constants.DefaultIndexURI); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"update"},
+		{"install", "--manifest-url", "https://example.com/foo.yaml"},
+		{"index", "add", "foo", "https://example.com/foo/index.git"},
+	} {
+		out, err := test.Krew(append([]string{"--offline"}, args...)...).Run()
+		if err == nil {
+			t.Errorf("expected %q to fail in offline mode", args)
+		} else if !strings.Contains(string(out), "needs network access") {
+			t.Errorf("expected %q to fail with an error about network access, got: %s", args, out)
+		}
+	}
+}
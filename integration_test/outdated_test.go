@@ -0,0 +1,55 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrationtest
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/krew/internal/environment"
+)
+
+func TestKrewOutdated(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex().Krew("install", validPlugin, validPlugin2).RunOrFail()
+	if out := test.Krew("outdated").RunOrFailOutput(); len(out) != 0 {
+		t.Fatalf("expected no outdated plugins right after install, got:\n%s", out)
+	}
+	test.Krew("outdated", "--exit-code").RunOrFail()
+
+	receiptPath := environment.NewPaths(test.Root()).PluginInstallReceiptPath(validPlugin)
+	modifyManifestVersion(t, receiptPath, "v0.0.0")
+
+	out := string(test.Krew("outdated").RunOrFailOutput())
+	if !regexp.MustCompile(`(?m)^` + validPlugin + `\s+v0\.0\.0\s+v\S+\s+default\s+false$`).MatchString(out) {
+		t.Fatalf("expected %s to be listed as outdated, got:\n%s", validPlugin, out)
+	}
+	if diff := cmp.Diff([]string{validPlugin}, lines(test.Krew("outdated", "-o", "name").RunOrFailOutput())); diff != "" {
+		t.Fatalf("'outdated -o name' output doesn't match:\n%s", diff)
+	}
+	if _, err := test.Krew("outdated", "--exit-code").Run(); err == nil {
+		t.Fatal("expected outdated --exit-code to fail when upgrades are available")
+	}
+
+	all := lines(test.Krew("outdated", "--all", "-o", "name").RunOrFailOutput())
+	if diff := cmp.Diff([]string{validPlugin, validPlugin2}, all); diff != "" {
+		t.Fatalf("'outdated --all' output doesn't match:\n%s", diff)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrationtest
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+func writePolicy(test *ITest, rules string) string {
+	return test.TempDir().Write("policy.yaml", []byte(`apiVersion: policy.krew.sigs.k8s.io/v1alpha1
+kind: Policy
+`+rules)).Path("policy.yaml")
+}
+
+func TestKrewPolicy_DeniedPlugin(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+	test.WithDefaultIndex()
+	test.WithEnv("KREW_POLICY", writePolicy(test, "deniedPlugins:\n- "+validPlugin+"\n"))
+
+	out, err := test.Krew("install", validPlugin).Run()
+	if err == nil {
+		t.Fatal("expected installing a denied plugin to fail")
+	}
+	if !strings.Contains(string(out), "policy violation") {
+		t.Errorf("expected policy violation error, got: %s", out)
+	}
+	test.AssertExecutableNotInPATH("kubectl-" + validPlugin)
+}
+
+func TestKrewPolicy_AllowedIndexURLs(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+	test.WithDefaultIndex()
+	test.WithEnv("KREW_POLICY", writePolicy(test, "allowedIndexURLs:\n- https://git.example.com/*\n"))
+
+	indexPath := environment.NewPaths(test.Root()).IndexPath(constants.DefaultIndexName)
+	out, err := test.Krew("index", "add", "foo", indexPath).Run()
+	if err == nil {
+		t.Fatal("expected adding an index from a URL that is not allowed to fail")
+	}
+	if !strings.Contains(string(out), "policy violation") {
+		t.Errorf("expected policy violation error, got: %s", out)
+	}
+}
+
+func TestKrewPolicy_MissingPolicyFile(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+	test.WithDefaultIndex()
+	test.WithEnv("KREW_POLICY", test.TempDir().Path("missing.yaml"))
+
+	if _, err := test.Krew("install", validPlugin).Run(); err == nil {
+		t.Fatal("expected install to fail when the policy in KREW_POLICY does not exist")
+	}
+	test.AssertExecutableNotInPATH("kubectl-" + validPlugin)
+}
@@ -15,10 +15,18 @@
 package integrationtest
 
 import (
+	"encoding/json"
+	"os"
 	"regexp"
 	"sort"
 	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/gitutil"
+	"sigs.k8s.io/krew/pkg/constants"
 )
 
 func TestKrewSearchAll(t *testing.T) {
@@ -85,3 +93,126 @@ func TestKrewSearchMultiIndexSortedByDisplayName(t *testing.T) {
 		t.Fatalf("names are not sorted: [%s]", strings.Join(names, ", "))
 	}
 }
+
+func TestKrewSearch_OutputFormats(t *testing.T) {
+	skipShort(t)
+	test := NewTest(t)
+	test = test.WithDefaultIndex()
+
+	names := lines(test.Krew("search", "-o", "name").RunOrFailOutput())
+	if len(names) < 10 {
+		t.Fatalf("expected at least 10 plugin names, got: %v", names)
+	}
+	if !sort.StringsAreSorted(names) {
+		t.Fatalf("names are not sorted: [%s]", strings.Join(names, ", "))
+	}
+
+	var list struct {
+		Kind  string `json:"kind"`
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+	out := test.Krew("search", validPlugin, "-o", "json").RunOrFailOutput()
+	if err := json.Unmarshal(out, &list); err != nil {
+		t.Fatalf("cannot parse search output as json: %v\n%s", err, out)
+	}
+	if list.Kind != "PluginList" || len(list.Items) == 0 {
+		t.Fatalf("unexpected search output: %s", out)
+	}
+
+	out = test.Krew("search", "-o", "jsonpath={.items[0].kind}").RunOrFailOutput()
+	if string(out) != "Plugin" {
+		t.Fatalf("unexpected jsonpath output: %q", out)
+	}
+}
+
+func TestKrewSearch_Filters(t *testing.T) {
+	skipShort(t)
+	test := NewTest(t)
+	test = test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+
+	test.Krew("install", validPlugin).RunOrFail()
+
+	names := lines(test.Krew("search", "--installed", "-o", "name").RunOrFailOutput())
+	if diff := cmp.Diff([]string{validPlugin}, names); diff != "" {
+		t.Errorf("installed plugins mismatch: %s", diff)
+	}
+
+	names = lines(test.Krew("search", "--index=foo", "-o", "name").RunOrFailOutput())
+	if len(names) < 10 {
+		t.Fatalf("expected at least 10 plugins in index foo, got: %v", names)
+	}
+	for _, n := range names {
+		if !strings.HasPrefix(n, "foo/") {
+			t.Errorf("expected only plugins from index foo, got %q", n)
+		}
+	}
+
+	if _, err := test.Krew("search", "--index=unknown").Run(); err == nil {
+		t.Error("expected search in a nonexistent index to fail")
+	}
+	if _, err := test.Krew("search", "--available-on=linux").Run(); err == nil {
+		t.Error("expected search with an invalid platform to fail")
+	}
+	all := lines(test.Krew("search", "--index=default", "-o", "name").RunOrFailOutput())
+	available := lines(test.Krew("search", "--index=default", "--available-on=linux/amd64", "-o", "name").RunOrFailOutput())
+	if len(available) == 0 || len(available) > len(all) {
+		t.Errorf("unexpected number of plugins available on linux/amd64: %d of %d", len(available), len(all))
+	}
+
+	var list struct {
+		Items []struct {
+			Name      string `json:"name"`
+			Available bool   `json:"available"`
+		} `json:"items"`
+	}
+	out := test.WithEnv("KREW_OS", "unknown").
+		Krew("search", "--index=default", "--available-on=linux/amd64", "-o", "json").RunOrFailOutput()
+	if err := json.Unmarshal(out, &list); err != nil {
+		t.Fatalf("cannot parse search output as json: %v\n%s", err, out)
+	}
+	for _, p := range list.Items {
+		if !p.Available {
+			t.Errorf("expected %q to be marked available on linux/amd64", p.Name)
+		}
+	}
+}
+
+func TestKrewSearch_RanksExactNameFirst(t *testing.T) {
+	skipShort(t)
+	test := NewTest(t)
+
+	names := lines(test.WithDefaultIndex().Krew("search", validPlugin, "-o", "name").RunOrFailOutput())
+	if len(names) == 0 || names[0] != validPlugin {
+		t.Fatalf("expected %q to be the first match, got: %v", validPlugin, names)
+	}
+}
+
+func TestKrewSearch_CachedPluginListFollowsIndex(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	test.WithDefaultIndex()
+	p := environment.NewPaths(test.Root())
+	if out := string(test.Krew("search", validPlugin).RunOrFailOutput()); !strings.Contains(out, validPlugin) {
+		t.Fatalf("expected search to find %q:\n%s", validPlugin, out)
+	}
+	if _, err := os.Stat(p.IndexCachePath(constants.DefaultIndexName)); err != nil {
+		t.Fatalf("expected search to cache the plugin list: %v", err)
+	}
+
+	indexPath := p.IndexPath(constants.DefaultIndexName)
+	for _, args := range [][]string{
+		{"rm", "-q", "plugins/" + validPlugin + constants.ManifestExtension},
+		{"-c", "user.name=krew", "-c", "user.email=krew@example.com", "commit", "-q", "-m", "remove plugin"},
+	} {
+		if _, err := gitutil.Exec(indexPath, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out := string(test.Krew("search", validPlugin).RunOrFailOutput()); strings.Contains(out, validPlugin+" ") {
+		t.Errorf("expected search not to find the removed plugin %q:\n%s", validPlugin, out)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrationtest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+// newSigningKey creates an OpenPGP key and writes its public key to a file in
+// the test directory.
+func newSigningKey(t *testing.T, test *ITest) (*openpgp.Entity, string) {
+	t.Helper()
+	key, err := openpgp.NewEntity("krew", "", "krew@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pub bytes.Buffer
+	w, err := armor.Encode(&pub, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := key.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return key, test.TempDir().Write("key.asc", pub.Bytes()).Path("key.asc")
+}
+
+// commitPlugin adds the manifest of validPlugin from the default index to the
+// repository at dir and commits it, signed with key unless it is nil.
+func commitPlugin(t *testing.T, test *ITest, dir string, key *openpgp.Entity) {
+	t.Helper()
+	r, err := git.PlainOpen(dir)
+	if err == git.ErrRepositoryNotExists {
+		r, err = git.PlainInit(dir, false)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(environment.NewPaths(test.Root()).IndexPluginsPath(constants.DefaultIndexName), validPlugin+constants.ManifestExtension)
+	manifest, err := ioutil.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest = append(manifest, []byte("\n# "+time.Now().String()+"\n")...)
+	if err := ioutil.WriteFile(filepath.Join(dir, "plugins", validPlugin+constants.ManifestExtension), manifest, 0644); err != nil {
+		t.Fatal(err)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Add("plugins"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Commit("update plugins", &git.CommitOptions{
+		Author:  &object.Signature{Name: "krew", Email: "krew@example.com", When: time.Now()},
+		SignKey: key,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKrewIndexAdd_SigningKey(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+	test.WithDefaultIndex()
+	key, keyFile := newSigningKey(t, test)
+
+	out, err := test.Krew("index", "add", "unsigned",
+		environment.NewPaths(test.Root()).IndexPath(constants.DefaultIndexName), "--signing-key", keyFile).Run()
+	if err == nil {
+		t.Fatal("expected adding an index with unsigned commits to fail")
+	}
+	if !strings.Contains(string(out), "failed verification") {
+		t.Errorf("expected verification error, got: %s", out)
+	}
+
+	repo := test.TempDir().Write("signed/plugins/.keep", nil).Path("signed")
+	commitPlugin(t, test, repo, key)
+	test.Krew("index", "add", "signed", repo, "--signing-key", keyFile).RunOrFail()
+	test.Krew("info", "signed/"+validPlugin).RunOrFail()
+
+	commitPlugin(t, test, repo, nil)
+	if _, err := test.Krew("update", "signed").Run(); err == nil {
+		t.Error("expected update to an unsigned commit to fail")
+	}
+	out, err = test.Krew("info", "signed/"+validPlugin).Run()
+	if err == nil {
+		t.Fatal("expected loading a plugin from an index that failed verification to fail")
+	}
+	if !strings.Contains(string(out), "failed verification") {
+		t.Errorf("expected verification error, got: %s", out)
+	}
+}
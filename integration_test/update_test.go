@@ -68,6 +68,21 @@ func TestKrewUpdateMultipleIndexes(t *testing.T) {
 	}
 }
 
+func TestKrewUpdate_SelectedIndexes(t *testing.T) {
+	skipShort(t)
+	test := NewTest(t)
+
+	test = test.WithDefaultIndex().WithCustomIndexFromDefault("foo")
+	out := lines(test.Krew("update", "foo").RunOrFailOutput())
+	if len(out) != 2 || !strings.HasPrefix(out[1], "foo ") {
+		t.Errorf("expected summary with only index foo, got:\n%s", strings.Join(out, "\n"))
+	}
+
+	if _, err := test.Krew("update", "does-not-exist").Run(); err == nil {
+		t.Error("expected update of an unknown index to fail")
+	}
+}
+
 func TestKrewUpdateFailedIndex(t *testing.T) {
 	skipShort(t)
 	test := NewTest(t)
@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/gitutil"
 	"sigs.k8s.io/krew/pkg/constants"
 )
 
@@ -231,3 +232,45 @@ func modifyReceiptIndex(t *testing.T, file, index string) {
 		t.Fatal(err)
 	}
 }
+
+func TestKrewUpgrade_ReplacedPlugin(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+	test.WithDefaultIndex()
+	test.Krew("install", validPlugin).RunOrFail()
+
+	manifest := filepath.Join(environment.NewPaths(test.Root()).IndexPluginsPath(constants.DefaultIndexName), validPlugin+constants.ManifestExtension)
+	b, err := ioutil.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = regexp.MustCompile(`(?m)^apiVersion: .*$`).ReplaceAll(b, []byte("apiVersion: krew.googlecontainertools.github.com/v1beta1"))
+	b = regexp.MustCompile(`(?m)^spec:\n`).ReplaceAll(b, []byte("spec:\n  deprecated: renamed\n  replacedBy: "+validPlugin2+"\n"))
+	if err := ioutil.WriteFile(manifest, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// commit the change, so that the cached plugin list of the index is not used
+	if _, err := gitutil.Exec(filepath.Dir(filepath.Dir(manifest)), "-c", "user.name=krew", "-c", "user.email=krew@example.com",
+		"commit", "-qam", "deprecate "+validPlugin); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := string(test.Krew("info", validPlugin).RunOrFailOutput()); !strings.Contains(out, "REPLACED BY: "+validPlugin2) {
+		t.Errorf("expected info to show the replacement:\n%s", out)
+	}
+	if out := string(test.Krew("list", "-o", "json").RunOrFailOutput()); !strings.Contains(out, `"replacedBy": "`+validPlugin2+`"`) {
+		t.Errorf("expected list to show the replacement:\n%s", out)
+	}
+	// fails because the newest version is already installed
+	out, _ := test.Krew("upgrade", validPlugin, "--no-update-index").Run()
+	if !strings.Contains(string(out), "upgrade --replace "+validPlugin) {
+		t.Errorf("expected upgrade to offer the replacement:\n%s", out)
+	}
+	test.AssertExecutableInPATH("kubectl-" + validPlugin)
+
+	test.Krew("upgrade", validPlugin, "--replace", "--no-update-index").RunOrFail()
+	test.AssertExecutableInPATH("kubectl-" + validPlugin2)
+	test.AssertExecutableNotInPATH("kubectl-" + validPlugin)
+	test.AssertPluginFromIndex(validPlugin2, constants.DefaultIndexName)
+}
@@ -84,3 +84,17 @@ func checkRequiredSubstrings(test *ITest, index, stdOut string) error {
 
 	return nil
 }
+
+func TestKrewCompletion(t *testing.T) {
+	skipShort(t)
+
+	test := NewTest(t)
+
+	out := string(test.Krew("completion", "bash").RunOrFailOutput())
+	if !strings.Contains(out, "bash completion") {
+		t.Errorf("expected a bash completion script, got:\n%s", out)
+	}
+	if _, err := test.Krew("completion", "tcsh").Run(); err == nil {
+		t.Error("expected unsupported shell to fail")
+	}
+}
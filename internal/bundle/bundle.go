@@ -0,0 +1,267 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle packs plugin manifests and their archives into a tar file, so
+// that plugins can be installed on hosts without network access.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/krew/internal/download"
+	"sigs.k8s.io/krew/internal/installation"
+	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/scheme"
+)
+
+// Supported version of the bundle metadata.
+const (
+	APIVersion = "bundle.krew.sigs.k8s.io/v1alpha1"
+	Kind       = "Bundle"
+)
+
+// MetadataFile is the path of the bundle metadata in the bundle.
+const MetadataFile = "bundle.yaml"
+
+// Metadata describes the contents of a bundle.
+type Metadata struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Platform is the os/arch pair that the archives were selected for.
+	Platform string  `json:"platform"`
+	Plugins  []Entry `json:"plugins"`
+}
+
+// Entry is a plugin in a bundle. The manifest and archive paths are
+// slash-separated and relative to the root of the bundle.
+type Entry struct {
+	Name     string `json:"name"`
+	Index    string `json:"index"`
+	Manifest string `json:"manifest"`
+	Archive  string `json:"archive"`
+}
+
+// Source is a plugin to add to a bundle and the index it was loaded from.
+type Source struct {
+	Plugin index.Plugin
+	Index  string
+}
+
+// Create writes a bundle of the given plugins for platform to w. The archive
+// of each plugin is downloaded with fetcher and verified against the sha256
+// sum in its manifest before it is added.
+func Create(w io.Writer, platform installation.OSArchPair, plugins []Source, fetcher download.Fetcher) error {
+	tw := tar.NewWriter(w)
+	m := Metadata{APIVersion: APIVersion, Kind: Kind, Platform: platform.String()}
+	seen := make(map[string]bool, len(plugins))
+	for _, src := range plugins {
+		name := src.Plugin.Name
+		if seen[name] {
+			return errors.Errorf("plugin %q is listed more than once", name)
+		}
+		seen[name] = true
+
+		p, ok, err := installation.GetMatchingPlatformFor(src.Plugin.Spec.Platforms, platform)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find a matching platform for plugin %q", name)
+		}
+		if !ok {
+			return errors.Errorf("plugin %q does not offer installation for platform %q", name, platform)
+		}
+		manifest, err := scheme.EncodePlugin(src.Plugin)
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert manifest of plugin %q to yaml", name)
+		}
+
+		e := Entry{
+			Name:     name,
+			Index:    src.Index,
+			Manifest: path.Join("manifests", src.Index, name+".yaml"),
+			Archive:  path.Join("archives", src.Index, name, archiveName(p.URI)),
+		}
+		if err := writeFile(tw, e.Manifest, manifest); err != nil {
+			return err
+		}
+		if err := addArchive(tw, e.Archive, fetcher, p.URI, p.Sha256); err != nil {
+			return errors.Wrapf(err, "failed to add the archive of plugin %q", name)
+		}
+		m.Plugins = append(m.Plugins, e)
+	}
+
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert bundle metadata to yaml")
+	}
+	if err := writeFile(tw, MetadataFile, b); err != nil {
+		return err
+	}
+	return errors.Wrap(tw.Close(), "failed to finish bundle")
+}
+
+// addArchive downloads uri and adds it to the bundle as name. The archive is
+// streamed through a temporary file, since the tar header needs its size, and
+// its sha256 sum is checked while it is written.
+func addArchive(tw *tar.Writer, name string, fetcher download.Fetcher, uri, sha256sum string) error {
+	f, err := fetchVerified(fetcher, uri, sha256sum)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the downloaded archive %q", uri)
+	}
+	return writeEntry(tw, name, f, fi.Size())
+}
+
+// fetchVerified downloads uri into a temporary file and checks its sha256 sum.
+// The file is returned at its start, the caller closes and removes it.
+func fetchVerified(fetcher download.Fetcher, uri, sha256sum string) (*os.File, error) {
+	body, err := fetcher.Get(uri)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download %q", uri)
+	}
+	defer body.Close()
+
+	f, err := ioutil.TempFile("", "krew-bundle-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a temporary file for the archive")
+	}
+	verifier := download.NewSha256Verifier(sha256sum)
+	if _, err = io.Copy(io.MultiWriter(f, verifier), body); err != nil {
+		err = errors.Wrapf(err, "failed to download %q", uri)
+	} else if err = verifier.Verify(); err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return f, nil
+}
+
+// archiveName returns the file name of the archive at uri.
+func archiveName(uri string) string {
+	if i := strings.IndexAny(uri, "?#"); i >= 0 {
+		uri = uri[:i]
+	}
+	if name := path.Base(uri); name != "." && name != "/" && name != "" {
+		return name
+	}
+	return "archive"
+}
+
+func writeFile(tw *tar.Writer, name string, b []byte) error {
+	return writeEntry(tw, name, bytes.NewReader(b), int64(len(b)))
+}
+
+// writeEntry adds a file of the given size with the contents of r to the
+// bundle.
+func writeEntry(tw *tar.Writer, name string, r io.Reader, size int64) error {
+	klog.V(3).Infof("Adding %q to bundle", name)
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     size,
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to add %q to bundle", name)
+	}
+	_, err := io.Copy(tw, r)
+	return errors.Wrapf(err, "failed to add %q to bundle", name)
+}
+
+// Extract unpacks the bundle in r into dir and returns its metadata. Paths in
+// the returned metadata are relative to dir.
+func Extract(r io.Reader, dir string) (Metadata, error) {
+	var m Metadata
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return m, errors.Wrap(err, "failed to read bundle")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return m, errors.Errorf("bundle entry %q is not a regular file", hdr.Name)
+		}
+		dst, err := safePath(dir, hdr.Name)
+		if err != nil {
+			return m, err
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return m, errors.Wrap(err, "failed to create directory")
+		}
+		f, err := os.Create(dst)
+		if err != nil {
+			return m, errors.Wrapf(err, "failed to create %q", dst)
+		}
+		_, err = io.Copy(f, tr)
+		f.Close()
+		if err != nil {
+			return m, errors.Wrapf(err, "failed to extract %q", hdr.Name)
+		}
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, MetadataFile))
+	if err != nil {
+		return m, errors.Wrap(err, "failed to read bundle metadata")
+	}
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return m, errors.Wrap(err, "failed to parse bundle metadata")
+	}
+	if m.APIVersion != APIVersion || m.Kind != Kind {
+		return m, errors.Errorf("unsupported bundle %s %s, expected %s %s", m.APIVersion, m.Kind, APIVersion, Kind)
+	}
+	for _, e := range m.Plugins {
+		for _, p := range []string{e.Manifest, e.Archive} {
+			if _, err := safePath(dir, p); err != nil {
+				return m, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// Path returns the location of a slash-separated bundle path in dir.
+func Path(dir, name string) string {
+	return filepath.Join(dir, filepath.FromSlash(name))
+}
+
+// safePath returns the location of a bundle entry in dir, and an error if the
+// entry would be outside of dir.
+func safePath(dir, name string) (string, error) {
+	clean := path.Clean(name)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") || strings.ContainsAny(clean, `\:`) {
+		return "", errors.Errorf("bundle entry %q has an unsafe path", name)
+	}
+	return Path(dir, clean), nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/krew/internal/installation"
+	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/index"
+)
+
+type fakeFetcher map[string]string
+
+func (f fakeFetcher) Get(uri string) (io.ReadCloser, error) {
+	content, ok := f[uri]
+	if !ok {
+		return nil, errors.Errorf("%q not found", uri)
+	}
+	return ioutil.NopCloser(strings.NewReader(content)), nil
+}
+
+func sha(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func plugin(name, uri, sha256sum string) index.Plugin {
+	return testutil.NewPlugin().WithName(name).WithPlatforms(
+		testutil.NewPlatform().WithOSArch("linux", "amd64").WithURI(uri).WithSHA256(sha256sum).V(),
+	).V()
+}
+
+var linuxAMD64 = installation.OSArchPair{OS: "linux", Arch: "amd64"}
+
+func TestCreateAndExtract(t *testing.T) {
+	fetcher := fakeFetcher{
+		"https://example.com/foo.tar.gz?raw=1": "foo-archive",
+		"https://example.com/bar.zip":          "bar-archive",
+	}
+	var buf bytes.Buffer
+	err := Create(&buf, linuxAMD64, []Source{
+		{Plugin: plugin("foo", "https://example.com/foo.tar.gz?raw=1", sha("foo-archive")), Index: "default"},
+		{Plugin: plugin("bar", "https://example.com/bar.zip", sha("bar-archive")), Index: "corp"},
+	}, fetcher)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := testutil.NewTempDir(t)
+	m, err := Extract(&buf, tmpDir.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Platform != "linux/amd64" {
+		t.Errorf("Platform = %q, want linux/amd64", m.Platform)
+	}
+	want := []Entry{
+		{Name: "foo", Index: "default", Manifest: "manifests/default/foo.yaml", Archive: "archives/default/foo/foo.tar.gz"},
+		{Name: "bar", Index: "corp", Manifest: "manifests/corp/bar.yaml", Archive: "archives/corp/bar/bar.zip"},
+	}
+	if len(m.Plugins) != len(want) {
+		t.Fatalf("got %d plugins, want %d", len(m.Plugins), len(want))
+	}
+	for i, e := range m.Plugins {
+		if e != want[i] {
+			t.Errorf("plugin %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+	b, err := ioutil.ReadFile(Path(tmpDir.Root(), want[1].Archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "bar-archive" {
+		t.Errorf("archive content = %q", b)
+	}
+	if _, err := os.Stat(Path(tmpDir.Root(), want[0].Manifest)); err != nil {
+		t.Errorf("manifest not extracted: %v", err)
+	}
+}
+
+func TestCreate_errors(t *testing.T) {
+	fetcher := fakeFetcher{"https://example.com/foo.tar.gz": "foo-archive"}
+	tests := []struct {
+		name    string
+		plugins []Source
+		env     installation.OSArchPair
+	}{
+		{
+			name:    "checksum mismatch",
+			plugins: []Source{{Plugin: plugin("foo", "https://example.com/foo.tar.gz", sha("other")), Index: "default"}},
+			env:     linuxAMD64,
+		},
+		{
+			name:    "unsupported platform",
+			plugins: []Source{{Plugin: plugin("foo", "https://example.com/foo.tar.gz", sha("foo-archive")), Index: "default"}},
+			env:     installation.OSArchPair{OS: "darwin", Arch: "arm64"},
+		},
+		{
+			name: "duplicate plugin",
+			plugins: []Source{
+				{Plugin: plugin("foo", "https://example.com/foo.tar.gz", sha("foo-archive")), Index: "default"},
+				{Plugin: plugin("foo", "https://example.com/foo.tar.gz", sha("foo-archive")), Index: "corp"},
+			},
+			env: linuxAMD64,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Create(ioutil.Discard, tt.env, tt.plugins, fetcher); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestCreate_removesDownloads(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	defer os.Setenv("TMPDIR", os.Getenv("TMPDIR"))
+	os.Setenv("TMPDIR", tmpDir.Root())
+
+	fetcher := fakeFetcher{"https://example.com/foo.tar.gz": "foo-archive"}
+	for _, sum := range []string{sha("foo-archive"), sha("other")} {
+		plugins := []Source{{Plugin: plugin("foo", "https://example.com/foo.tar.gz", sum), Index: "default"}}
+		_ = Create(ioutil.Discard, linuxAMD64, plugins, fetcher)
+		if entries, err := ioutil.ReadDir(tmpDir.Root()); err != nil || len(entries) != 0 {
+			t.Errorf("expected the downloaded archive to be removed, got %d files, %v", len(entries), err)
+		}
+	}
+}
+
+func TestExtract_unsafePath(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := writeFile(tw, "../evil", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tmpDir := testutil.NewTempDir(t)
+	if _, err := Extract(&buf, tmpDir.Path("bundle")); err == nil {
+		t.Error("expected error for entry outside of the bundle directory")
+	}
+}
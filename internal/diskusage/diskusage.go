@@ -0,0 +1,91 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diskusage computes the disk space occupied by krew directories.
+package diskusage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// Entry describes the disk usage of a single directory.
+type Entry struct {
+	Name  string
+	Path  string
+	Bytes int64
+}
+
+// Size returns the total size of regular files in the directory tree rooted
+// at path. Symbolic links are not followed. If path does not exist, it returns
+// an error that can be checked with os.IsNotExist.
+func Size(path string) (int64, error) {
+	var total int64
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// Subdirs returns the disk usage of each directory directly under path,
+// sorted by name. Files directly under path are ignored. If path does not
+// exist, it returns an empty list.
+func Subdirs(path string) ([]Entry, error) {
+	entries, err := ioutil.ReadDir(path)
+	if os.IsNotExist(err) {
+		klog.V(3).Infof("directory %q does not exist, assuming no disk usage", path)
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to list directory %q", path)
+	}
+
+	var out []Entry
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		p := filepath.Join(path, e.Name())
+		size, err := Size(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compute disk usage of %q", p)
+		}
+		out = append(out, Entry{Name: e.Name(), Path: p, Bytes: size})
+	}
+	return out, nil
+}
+
+// HumanReadable formats a byte count using binary (IEC) units, e.g. "1.5 MiB".
+func HumanReadable(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
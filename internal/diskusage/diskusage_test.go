@@ -0,0 +1,92 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diskusage
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/krew/internal/testutil"
+)
+
+func TestSize(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("a/file1", make([]byte, 10))
+	tmpDir.Write("a/b/file2", make([]byte, 32))
+	if err := os.Symlink(tmpDir.Path("a/file1"), tmpDir.Path("a/link")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Size(tmpDir.Path("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 42 {
+		t.Errorf("Size() = %d, want 42", got)
+	}
+
+	if _, err := Size(tmpDir.Path("does-not-exist")); !os.IsNotExist(err) {
+		t.Errorf("expected ENOENT error, got: %v", err)
+	}
+}
+
+func TestSubdirs(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("root/foo/v1.0.0/bin", make([]byte, 100))
+	tmpDir.Write("root/bar/v2.0.0/bin", make([]byte, 5))
+	tmpDir.Write("root/bar/v2.0.0/LICENSE", make([]byte, 5))
+	tmpDir.Write("root/not-a-dir", make([]byte, 1))
+
+	got, err := Subdirs(tmpDir.Path("root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{Name: "bar", Path: tmpDir.Path("root/bar"), Bytes: 10},
+		{Name: "foo", Path: tmpDir.Path("root/foo"), Bytes: 100},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Subdirs() mismatch: %s", diff)
+	}
+
+	got, err = Subdirs(tmpDir.Path("does-not-exist"))
+	if err != nil {
+		t.Fatalf("expected no error for missing directory, got: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no entries for missing directory, got: %v", got)
+	}
+}
+
+func TestHumanReadable(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := HumanReadable(tt.in); got != tt.want {
+			t.Errorf("HumanReadable(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -209,6 +209,11 @@ func extractArchive(dst string, at io.ReaderAt, size int64) error {
 
 }
 
+// ExtractArchive extracts the zip or tar.gz archive read from at into dst.
+func ExtractArchive(dst string, at io.ReaderAt, size int64) error {
+	return extractArchive(dst, at, size)
+}
+
 // Downloader is responsible for fetching, verifying and extracting a binary.
 type Downloader struct {
 	verifier Verifier
@@ -15,12 +15,15 @@
 package download
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"os"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/internal/network"
 )
 
 // Fetcher is used to get files from a URI.
@@ -36,6 +39,9 @@ type HTTPFetcher struct{}
 
 // Get gets the file and returns an stream to read the file.
 func (HTTPFetcher) Get(uri string) (io.ReadCloser, error) {
+	if err := network.Check(fmt.Sprintf("downloading %q", uri)); err != nil {
+		return nil, err
+	}
 	klog.V(2).Infof("Fetching %q", uri)
 	resp, err := http.Get(uri)
 	if err != nil {
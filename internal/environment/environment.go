@@ -66,6 +66,39 @@ func (p Paths) IndexPath(name string) string {
 	return filepath.Join(p.base, "index", name)
 }
 
+// IndexConfigPath returns the path of the file that lists the configured
+// indexes.
+//
+// e.g. {BasePath}/indexes.yaml
+func (p Paths) IndexConfigPath() string { return filepath.Join(p.base, "indexes.yaml") }
+
+// CachePath returns the directory of files that krew can recreate, such as
+// the parsed plugin lists of indexes.
+//
+// e.g. {BasePath}/cache
+func (p Paths) CachePath() string { return filepath.Join(p.base, "cache") }
+
+// IndexCachePath returns the path of the parsed plugin list of an index.
+//
+// e.g. {CachePath}/index/{name}.json
+func (p Paths) IndexCachePath(name string) string {
+	return filepath.Join(p.CachePath(), "index", name+".json")
+}
+
+// StatePath returns the directory of files that record what krew did, such as
+// when indexes were last updated.
+//
+// e.g. {BasePath}/state
+func (p Paths) StatePath() string { return filepath.Join(p.base, "state") }
+
+// IndexStatePath returns the path of the file that records the state of an
+// index.
+//
+// e.g. {StatePath}/index/{name}.json
+func (p Paths) IndexStatePath(name string) string {
+	return filepath.Join(p.StatePath(), "index", name+".json")
+}
+
 // IndexPluginsPath returns the plugins directory of an index repository.
 // e.g. {BasePath}/index/default/plugins/ or {BasePath}/index/plugins/
 func (p Paths) IndexPluginsPath(name string) string {
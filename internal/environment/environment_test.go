@@ -63,6 +63,23 @@ func TestPaths(t *testing.T) {
 		t.Errorf("IndexPluginsPath(\"%s\")=%s; expected=%s", constants.DefaultIndexName, got, expected)
 	}
 
+	if got, expected := p.IndexConfigPath(), filepath.FromSlash("/foo/indexes.yaml"); got != expected {
+		t.Errorf("IndexConfigPath()=%s; expected=%s", got, expected)
+	}
+
+	if got, expected := p.CachePath(), filepath.FromSlash("/foo/cache"); got != expected {
+		t.Errorf("CachePath()=%s; expected=%s", got, expected)
+	}
+	if got, expected := p.IndexCachePath("foo"), filepath.FromSlash("/foo/cache/index/foo.json"); got != expected {
+		t.Errorf("IndexCachePath()=%s; expected=%s", got, expected)
+	}
+	if got, expected := p.StatePath(), filepath.FromSlash("/foo/state"); got != expected {
+		t.Errorf("StatePath()=%s; expected=%s", got, expected)
+	}
+	if got, expected := p.IndexStatePath("foo"), filepath.FromSlash("/foo/state/index/foo.json"); got != expected {
+		t.Errorf("IndexStatePath()=%s; expected=%s", got, expected)
+	}
+
 	if got, expected := p.InstallPath(), filepath.FromSlash("/foo/store"); got != expected {
 		t.Errorf("InstallPath()=%s; expected=%s", got, expected)
 	}
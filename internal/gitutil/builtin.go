@@ -0,0 +1,448 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitutil
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	refConfigSection = "krew"
+	refConfigOption  = "ref"
+)
+
+// builtinClient implements the git operations in-process, so that the git
+// binary is not required. Unless KREW_NO_SHALLOW_CLONE is set, clones only
+// contain the latest commit and only the plugins directory is checked out,
+// like the clones of the git binary.
+//
+// Clones are created in a temporary directory next to their destination and
+// only replace it once they are complete, so that a failed clone never
+// removes the existing local copy of an index.
+type builtinClient struct{}
+
+func (c builtinClient) ensureClonedAtRef(uri, destinationPath, ref string) error {
+	shallow := !shallowClonesDisabled()
+	return c.cloneInPlace(context.Background(), uri, destinationPath, ref, shallow, shallow)
+}
+
+// cloneInPlace clones uri into a temporary directory next to dir, and
+// replaces dir with the clone once it succeeded. Shallow clones only contain
+// the latest commit, and sparse clones only check out the plugins directory.
+func (c builtinClient) cloneInPlace(ctx context.Context, uri, dir, ref string, shallow, sparse bool) error {
+	parent := filepath.Dir(dir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory %q", parent)
+	}
+	tmp, err := ioutil.TempDir(parent, "."+filepath.Base(dir)+"-clone-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create a temporary directory for the clone")
+	}
+	defer os.RemoveAll(tmp)
+
+	klog.V(2).Infof("Cloning %q into %q", uri, dir)
+	if err := c.clone(ctx, uri, tmp, ref, shallow, sparse); err != nil {
+		return errors.Wrapf(err, "failed to clone %q", uri)
+	}
+	return replaceDir(tmp, dir)
+}
+
+func (c builtinClient) clone(ctx context.Context, uri, dir, ref string, shallow, sparse bool) error {
+	opts := &git.CloneOptions{URL: uri, Tags: git.AllTags, NoCheckout: true}
+	if shallow {
+		opts.Depth, opts.Tags = 1, git.NoTags
+	}
+	r, err := git.PlainCloneContext(ctx, dir, false, opts)
+	if err == transport.ErrEmptyRemoteRepository {
+		klog.V(1).Infof("Cloned an empty repository %q", uri)
+		_, err = initEmpty(uri, dir)
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	cfg, err := r.Config()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read config of %q", dir)
+	}
+	if ref != "" {
+		cfg.Raw.Section(refConfigSection).SetOption(refConfigOption, ref)
+	}
+	if sparse {
+		cfg.Raw.Section("core").SetOption("sparseCheckout", "true")
+		cfg.Raw.Section("core").SetOption("sparseCheckoutCone", "true")
+	}
+	if err := r.SetConfig(cfg); err != nil {
+		return errors.Wrapf(err, "failed to write config of %q", dir)
+	}
+	if sparse {
+		// the patterns that "git sparse-checkout set" writes in cone mode, so
+		// that the git binary only checks out the same directory
+		patterns := "/*\n!/*/\n/" + sparseCheckoutDir + "/\n"
+		if err := os.MkdirAll(filepath.Dir(sparseCheckoutFile(dir)), 0755); err != nil {
+			return errors.Wrapf(err, "failed to enable sparse checkout in %q", dir)
+		}
+		if err := ioutil.WriteFile(sparseCheckoutFile(dir), []byte(patterns), 0644); err != nil {
+			return errors.Wrapf(err, "failed to enable sparse checkout in %q", dir)
+		}
+	}
+
+	target, err := c.resolveRef(r, ref)
+	if err != nil {
+		// the ref is not one of the fetched branches
+		if target, err = c.fetch(ctx, r, dir, ref); err != nil {
+			return err
+		}
+		if r, err = git.PlainOpen(dir); err != nil {
+			return errors.Wrapf(err, "failed to open %q", dir)
+		}
+	}
+	return c.checkout(r, dir, target)
+}
+
+// initEmpty creates a repository without commits that has uri as its origin,
+// which is what cloning an empty repository does.
+func initEmpty(uri, dir string) (*git.Repository, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		return nil, err
+	}
+	_, err = r.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{uri}})
+	return r, err
+}
+
+// replaceDir moves the directory src to dst. An existing dst is only removed
+// once src took its place.
+func replaceDir(src, dst string) error {
+	old := src + ".old"
+	if err := os.Rename(dst, old); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to move %q out of the way", dst)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		if restoreErr := os.Rename(old, dst); restoreErr != nil && !os.IsNotExist(restoreErr) {
+			klog.Warningf("failed to restore %q: %v", dst, restoreErr)
+		}
+		return errors.Wrapf(err, "failed to move the clone to %q", dst)
+	}
+	return errors.Wrapf(os.RemoveAll(old), "failed to remove the previous clone of %q", dst)
+}
+
+func sparseCheckoutFile(dir string) string {
+	return filepath.Join(dir, ".git", "info", "sparse-checkout")
+}
+
+// isSparse tells whether only the plugins directory is checked out in dir.
+func isSparse(dir string) (bool, error) {
+	_, err := os.Stat(sparseCheckoutFile(dir))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (builtinClient) getRef(dir string) (string, error) {
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open %q", dir)
+	}
+	cfg, err := r.Config()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read config of %q", dir)
+	}
+	return cfg.Raw.Section(refConfigSection).Option(refConfigOption), nil
+}
+
+func (builtinClient) isShallow(dir string) (bool, error) {
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to open %q", dir)
+	}
+	commits, err := r.Storer.Shallow()
+	return len(commits) > 0, err
+}
+
+// unshallow replaces the shallow clone at dir with a clone of the complete
+// history of the same remote, pinned to the same ref and with the same sparse
+// checkout. The shallow clone is kept if cloning fails.
+func (c builtinClient) unshallow(ctx context.Context, dir string) error {
+	uri, err := c.getRemoteURL(dir)
+	if err != nil {
+		return err
+	}
+	ref, err := c.getRef(dir)
+	if err != nil {
+		return err
+	}
+	sparse, err := isSparse(dir)
+	if err != nil {
+		return err
+	}
+	klog.V(1).Infof("Cloning %q again to fetch its history", dir)
+	return c.cloneInPlace(ctx, uri, dir, ref, false, sparse)
+}
+
+func (builtinClient) getRemoteURL(dir string) (string, error) {
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open %q", dir)
+	}
+	remote, err := r.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get remote of %q", dir)
+	}
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		return urls[0], nil
+	}
+	return "", errors.Errorf("remote of %q has no URL", dir)
+}
+
+func (builtinClient) setRemoteURL(dir, uri string) error {
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %q", dir)
+	}
+	cfg, err := r.Config()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read config of %q", dir)
+	}
+	remote, ok := cfg.Remotes[git.DefaultRemoteName]
+	if !ok {
+		return errors.Errorf("%q has no remote %q", dir, git.DefaultRemoteName)
+	}
+	remote.URLs = []string{uri}
+	return r.SetConfig(cfg)
+}
+
+func (builtinClient) getCommit(dir string) (string, error) {
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open %q", dir)
+	}
+	head, err := r.Head()
+	if err == plumbing.ErrReferenceNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Wrapf(err, "failed to get HEAD of %q", dir)
+	}
+	return head.Hash().String(), nil
+}
+
+func (c builtinClient) status(dir string) (Status, error) {
+	var s Status
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return s, errors.Wrapf(err, "failed to open %q", dir)
+	}
+	head, err := r.Head()
+	if err == plumbing.ErrReferenceNotFound {
+		return s, nil
+	} else if err != nil {
+		return s, errors.Wrapf(err, "failed to get HEAD of %q", dir)
+	}
+
+	sparse, err := isSparse(dir)
+	if err != nil {
+		return s, err
+	}
+	if sparse {
+		s.Dirty, err = sparseDirty(r, dir)
+	} else {
+		s.Dirty, err = dirty(r, dir)
+	}
+	if err != nil {
+		return s, errors.Wrapf(err, "failed to get the status of %q", dir)
+	}
+
+	ref, err := c.getRef(dir)
+	if err != nil {
+		return s, err
+	}
+	target, err := c.resolveRef(r, ref)
+	if err != nil {
+		klog.V(2).Infof("Cannot tell whether %q diverged: %v", dir, err)
+		return s, nil
+	}
+	s.Diverged = head.Hash() != target
+	return s, nil
+}
+
+func dirty(r *git.Repository, dir string) (bool, error) {
+	w, err := r.Worktree()
+	if err != nil {
+		return false, err
+	}
+	st, err := w.Status()
+	return err == nil && !st.IsClean(), err
+}
+
+// sparseDirty tells whether files in the sparse checkout of dir were changed
+// or added. The status of go-git cannot be used, since it reports the files
+// that are not checked out as deleted.
+func sparseDirty(r *git.Repository, dir string) (bool, error) {
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return false, err
+	}
+	tracked := map[string]bool{}
+	for _, e := range idx.Entries {
+		if e.SkipWorktree {
+			continue
+		}
+		tracked[e.Name] = true
+		b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(e.Name)))
+		if os.IsNotExist(err) {
+			return true, nil
+		} else if err != nil {
+			return false, err
+		}
+		if plumbing.ComputeHash(plumbing.BlobObject, b) != e.Hash {
+			return true, nil
+		}
+	}
+
+	var untracked bool
+	err = filepath.Walk(filepath.Join(dir, sparseCheckoutDir), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if !tracked[filepath.ToSlash(rel)] {
+			untracked = true
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return untracked, err
+}
+
+// resolveRef finds the commit to reset a clone to for the given ref. An empty
+// ref resolves to the remote-tracking branch of the current branch.
+func (builtinClient) resolveRef(r *git.Repository, ref string) (plumbing.Hash, error) {
+	if ref == "" {
+		head, err := r.Head()
+		if err != nil {
+			return plumbing.ZeroHash, errors.Wrap(err, "failed to get HEAD")
+		}
+		upstream, err := r.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, head.Name().Short()), true)
+		if err != nil {
+			return plumbing.ZeroHash, errors.Wrapf(err, "failed to get the upstream of %q", head.Name().Short())
+		}
+		return upstream.Hash(), nil
+	}
+	for _, rev := range []string{"refs/remotes/origin/" + ref, "refs/tags/" + ref, ref} {
+		if h, err := r.ResolveRevision(plumbing.Revision(rev)); err == nil {
+			return *h, nil
+		}
+	}
+	return plumbing.ZeroHash, errors.Errorf("ref %q not found", ref)
+}
+
+// fetch retrieves the latest revision of ref from origin and returns the
+// commit to reset the clone to. Shallow clones only fetch the tip of their
+// branches, and of the ref if it is a tag. If the ref is not a branch or tag
+// that can be fetched this way, the history of the clone is fetched to look
+// it up.
+func (c builtinClient) fetch(ctx context.Context, r *git.Repository, dir, ref string) (plumbing.Hash, error) {
+	shallow, err := c.isShallow(dir)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	klog.V(2).Infof("Fetching %q", dir)
+	opts := &git.FetchOptions{RemoteName: git.DefaultRemoteName, Force: true, Tags: git.AllTags}
+	if shallow {
+		opts.Depth, opts.Tags = 1, git.NoTags
+	}
+	if err := r.FetchContext(ctx, opts); err != nil && err != git.NoErrAlreadyUpToDate {
+		return plumbing.ZeroHash, err
+	}
+	if target, err := c.resolveRef(r, ref); err == nil || !shallow {
+		return target, err
+	}
+
+	tag := config.RefSpec("+refs/tags/" + ref + ":refs/tags/" + ref)
+	opts.RefSpecs = []config.RefSpec{tag}
+	if err := r.FetchContext(ctx, opts); err == nil || err == git.NoErrAlreadyUpToDate {
+		return c.resolveRef(r, ref)
+	} else if ctx.Err() != nil {
+		return plumbing.ZeroHash, err
+	}
+	klog.V(1).Infof("Could not fetch ref %q at depth 1", ref)
+	if err := fetchHistory(ctx, c, dir); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if r, err = git.PlainOpen(dir); err != nil {
+		return plumbing.ZeroHash, errors.Wrapf(err, "failed to open %q", dir)
+	}
+	return c.resolveRef(r, ref)
+}
+
+// checkout resets the worktree of dir to target and removes untracked files.
+// In sparse clones only the plugins directory is checked out.
+func (builtinClient) checkout(r *git.Repository, dir string, target plumbing.Hash) error {
+	w, err := r.Worktree()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the worktree of %q", dir)
+	}
+	sparse, err := isSparse(dir)
+	if err != nil {
+		return err
+	}
+	var dirs []string
+	if sparse {
+		dirs = []string{sparseCheckoutDir}
+	}
+	if err := w.ResetSparsely(&git.ResetOptions{Commit: target, Mode: git.HardReset}, dirs); err != nil {
+		return errors.Wrapf(err, "reset index at %q failed", dir)
+	}
+	return errors.Wrapf(w.Clean(&git.CleanOptions{Dir: true}), "clean index at %q failed", dir)
+}
+
+func (c builtinClient) updateAndCleanUntracked(ctx context.Context, destinationPath string) error {
+	ref, err := c.getRef(destinationPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the ref of index at %q", destinationPath)
+	}
+	r, err := git.PlainOpen(destinationPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %q", destinationPath)
+	}
+	target, err := c.fetch(ctx, r, destinationPath, ref)
+	if err != nil {
+		return errors.Wrapf(err, "fetch index at %q failed", destinationPath)
+	}
+	// the clone is replaced if its history had to be fetched
+	if r, err = git.PlainOpen(destinationPath); err != nil {
+		return errors.Wrapf(err, "failed to open %q", destinationPath)
+	}
+	return c.checkout(r, destinationPath, target)
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitutil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	osexec "os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// execClient runs the git binary.
+type execClient struct{}
+
+func (c execClient) ensureClonedAtRef(uri, destinationPath, ref string) error {
+	if shallowClonesDisabled() {
+		if _, err := Exec("", "clone", "-v", uri, destinationPath); err != nil {
+			return err
+		}
+		if ref == "" {
+			return nil
+		}
+	} else {
+		if _, err := Exec("", "clone", "-v", "--depth=1", "--no-checkout", uri, destinationPath); err != nil {
+			return err
+		}
+		if _, err := Exec(destinationPath, "sparse-checkout", "set", sparseCheckoutDir); err != nil {
+			klog.V(1).Infof("Sparse checkout is not available, checking out all files: %v", err)
+		}
+		if ref == "" {
+			if _, err := Exec(destinationPath, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+				klog.V(1).Infof("Cloned an empty repository into %q", destinationPath)
+				return nil
+			}
+			_, err := Exec(destinationPath, "reset", "--hard", "HEAD")
+			return errors.Wrapf(err, "failed to check out %q", destinationPath)
+		}
+	}
+	if _, err := Exec(destinationPath, "config", refConfigKey, ref); err != nil {
+		return errors.Wrapf(err, "failed to store ref for %q", destinationPath)
+	}
+	return c.updateAndCleanUntracked(context.Background(), destinationPath)
+}
+
+func (execClient) getRef(dir string) (string, error) {
+	return Exec(dir, "config", "--default", "", "--get", refConfigKey)
+}
+
+func (execClient) isShallow(dir string) (bool, error) {
+	out, err := Exec(dir, "rev-parse", "--is-shallow-repository")
+	return out == "true", err
+}
+
+func (execClient) unshallow(ctx context.Context, dir string) error {
+	_, err := execContext(ctx, dir, "fetch", "-v", "--unshallow", "--tags")
+	return errors.Wrapf(err, "failed to fetch the history of %q", dir)
+}
+
+func (execClient) getRemoteURL(dir string) (string, error) {
+	return Exec(dir, "config", "--get", "remote.origin.url")
+}
+
+func (execClient) setRemoteURL(dir, uri string) error {
+	_, err := Exec(dir, "remote", "set-url", "origin", uri)
+	return err
+}
+
+func (execClient) getCommit(dir string) (string, error) {
+	if _, err := Exec(dir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return "", nil
+	}
+	return Exec(dir, "rev-parse", "HEAD")
+}
+
+// resolveRef finds the revision to reset a clone to for the given ref. Branch
+// names resolve to the remote-tracking branch so that updates follow the
+// remote; tags and commits resolve to themselves.
+func (execClient) resolveRef(dir, ref string) (string, error) {
+	if ref == "" {
+		return "@{upstream}", nil
+	}
+	for _, rev := range []string{"refs/remotes/origin/" + ref, "refs/tags/" + ref, ref} {
+		if _, err := Exec(dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err == nil {
+			return rev, nil
+		}
+	}
+	return "", errors.Errorf("ref %q not found in %q", ref, dir)
+}
+
+func (c execClient) status(dir string) (Status, error) {
+	var s Status
+	out, err := Exec(dir, "status", "--porcelain")
+	if err != nil {
+		return s, err
+	}
+	s.Dirty = out != ""
+
+	head, err := c.getCommit(dir)
+	if err != nil || head == "" {
+		return s, err
+	}
+	ref, err := c.getRef(dir)
+	if err != nil {
+		return s, err
+	}
+	target, err := c.resolveRef(dir, ref)
+	if err != nil {
+		klog.V(2).Infof("Cannot tell whether %q diverged: %v", dir, err)
+		return s, nil
+	}
+	want, err := Exec(dir, "rev-parse", "--verify", "--quiet", target+"^{commit}")
+	if err != nil {
+		klog.V(2).Infof("Cannot tell whether %q diverged: %q not found", dir, target)
+		return s, nil
+	}
+	s.Diverged = head != want
+	return s, nil
+}
+
+// fetch retrieves the latest revision of ref from origin and returns the
+// revision to reset the clone to. Shallow clones only fetch the tip of the
+// ref. If the ref is not a branch or tag that can be fetched this way, the
+// history of the clone is fetched to look it up.
+func (c execClient) fetch(ctx context.Context, dir, ref string) (string, error) {
+	shallow, err := c.isShallow(dir)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case ref == "" && shallow:
+		_, err = execContext(ctx, dir, "fetch", "-v", "--depth=1")
+	case ref == "":
+		_, err = execContext(ctx, dir, "fetch", "-v")
+	case shallow:
+		if _, err := execContext(ctx, dir, "fetch", "-v", "--depth=1", "origin", ref); err == nil {
+			return "FETCH_HEAD", nil
+		} else if ctx.Err() != nil {
+			return "", err
+		}
+		klog.V(1).Infof("Could not fetch ref %q at depth 1", ref)
+		err = fetchHistory(ctx, c, dir)
+	default:
+		_, err = execContext(ctx, dir, "fetch", "-v", "--tags")
+	}
+	if err != nil {
+		return "", err
+	}
+	return c.resolveRef(dir, ref)
+}
+
+// update will fetch origin and set HEAD to origin/HEAD, or to the pinned ref
+// and also will create a pristine working directory by removing
+// untracked files and directories.
+func (c execClient) updateAndCleanUntracked(ctx context.Context, destinationPath string) error {
+	ref, err := c.getRef(destinationPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the ref of index at %q", destinationPath)
+	}
+	target, err := c.fetch(ctx, destinationPath, ref)
+	if err != nil {
+		return errors.Wrapf(err, "fetch index at %q failed", destinationPath)
+	}
+	if _, err := Exec(destinationPath, "reset", "--hard", target); err != nil {
+		return errors.Wrapf(err, "reset index at %q failed", destinationPath)
+	}
+
+	_, err = Exec(destinationPath, "clean", "-xfd")
+	return errors.Wrapf(err, "clean index at %q failed", destinationPath)
+}
+
+func Exec(pwd string, args ...string) (string, error) {
+	return execContext(context.Background(), pwd, args...)
+}
+
+// execContext runs git like Exec, and kills it when ctx is done.
+func execContext(ctx context.Context, pwd string, args ...string) (string, error) {
+	klog.V(4).Infof("Going to run git %s", strings.Join(args, " "))
+	cmd := osexec.CommandContext(ctx, "git", args...)
+	cmd.Dir = pwd
+	buf := bytes.Buffer{}
+	var w io.Writer = &buf
+	if klog.V(2).Enabled() {
+		w = io.MultiWriter(w, os.Stderr)
+	}
+	cmd.Stdout, cmd.Stderr = w, w
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "command execution failure, output=%q", buf.String())
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -15,8 +15,8 @@
 package gitutil
 
 import (
-	"bytes"
-	"io"
+	"context"
+	"fmt"
 	"os"
 	osexec "os/exec"
 	"path/filepath"
@@ -24,17 +24,131 @@ import (
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/internal/network"
+)
+
+const (
+	// refConfigKey is the git config key in the clone that stores the ref the
+	// clone is pinned to.
+	refConfigKey = "krew.ref"
+
+	// sparseCheckoutDir is the only directory checked out in shallow clones.
+	sparseCheckoutDir = "plugins"
+
+	// noShallowCloneEnv disables shallow and sparse clones when set.
+	noShallowCloneEnv = "KREW_NO_SHALLOW_CLONE"
+
+	// clientEnv selects the git implementation: "builtin" uses the
+	// in-process implementation, and "exec" runs the git binary. If it is
+	// not set, the git binary is only used for remotes accessed over ssh.
+	clientEnv = "KREW_GIT_CLIENT"
 )
 
+// client implements the git operations on the local copy of an index.
+type client interface {
+	// ensureClonedAtRef clones uri into dir and pins it to ref.
+	ensureClonedAtRef(uri, dir, ref string) error
+	// updateAndCleanUntracked fetches origin, resets the clone to the latest
+	// revision of its ref and removes untracked files.
+	updateAndCleanUntracked(ctx context.Context, dir string) error
+	getRef(dir string) (string, error)
+	isShallow(dir string) (bool, error)
+	unshallow(ctx context.Context, dir string) error
+	getRemoteURL(dir string) (string, error)
+	setRemoteURL(dir, uri string) error
+	// getCommit returns the commit checked out in dir, or an empty string if
+	// the repository has no commits.
+	getCommit(dir string) (string, error)
+	status(dir string) (Status, error)
+}
+
+// Status describes local changes to a clone.
+type Status struct {
+	// Dirty is set if tracked files were modified or untracked files were
+	// added.
+	Dirty bool
+	// Diverged is set if the checked out commit is not the one that the ref
+	// of the clone resolves to, e.g. because of local commits.
+	Diverged bool
+}
+
+// newClient returns the git implementation selected with KREW_GIT_CLIENT.
+func newClient() client {
+	switch v := os.Getenv(clientEnv); v {
+	case "exec":
+		return execClient{}
+	case "builtin", "":
+	default:
+		klog.Warningf("Ignoring unknown value %q of %s, must be \"exec\" or \"builtin\"", v, clientEnv)
+	}
+	return builtinClient{}
+}
+
+// clientFor returns the git implementation to use for the remote at uri. If
+// KREW_GIT_CLIENT is not set, remotes that are accessed over ssh use the git
+// binary when it is installed, so that its ssh configuration and keys apply.
+func clientFor(uri string) client {
+	if _, ok := os.LookupEnv(clientEnv); !ok && isSSHURL(uri) {
+		if _, err := osexec.LookPath("git"); err == nil {
+			return execClient{}
+		}
+	}
+	return newClient()
+}
+
 // EnsureCloned will clone into the destination path, otherwise will return no error.
 func EnsureCloned(uri, destinationPath string) error {
+	return EnsureClonedAtRef(uri, destinationPath, "")
+}
+
+// EnsureClonedAtRef will clone into the destination path and pin the clone to
+// the given branch, tag or commit. An empty ref tracks the remote default
+// branch. If the destination path is already cloned, it returns no error.
+//
+// Unless KREW_NO_SHALLOW_CLONE is set, the clone only contains the latest
+// commit and only the plugins directory is checked out.
+func EnsureClonedAtRef(uri, destinationPath, ref string) error {
 	if ok, err := IsGitCloned(destinationPath); err != nil {
 		return err
-	} else if !ok {
-		_, err = Exec("", "clone", "-v", uri, destinationPath)
+	} else if ok {
+		return nil
+	}
+	if err := checkNetwork(uri, "cloning"); err != nil {
 		return err
 	}
-	return nil
+	return clientFor(uri).ensureClonedAtRef(uri, destinationPath, ref)
+}
+
+// GetRef returns the branch, tag or commit the clone at dir is pinned to, or
+// an empty string if it tracks the remote default branch.
+func GetRef(dir string) (string, error) {
+	return newClient().getRef(dir)
+}
+
+// IsShallow tells whether the clone at dir has a truncated history.
+func IsShallow(dir string) (bool, error) {
+	return newClient().isShallow(dir)
+}
+
+// fetchHistory fetches the complete history of the clone at dir with c. It
+// is a no-op for clones that are not shallow. Operations that need more than
+// the latest commit, such as looking up a ref that cannot be fetched at
+// depth 1, call it before they use the history.
+func fetchHistory(ctx context.Context, c client, dir string) error {
+	shallow, err := c.isShallow(dir)
+	if err != nil || !shallow {
+		return err
+	}
+	uri, err := c.getRemoteURL(dir)
+	if err != nil {
+		return err
+	}
+	if err := checkNetwork(uri, "fetching the history of"); err != nil {
+		return err
+	}
+	klog.V(1).Infof("Fetching the complete history of %q", dir)
+	return c.unshallow(ctx, dir)
 }
 
 // IsGitCloned will test if the path is a git dir.
@@ -46,47 +160,84 @@ func IsGitCloned(gitPath string) (bool, error) {
 	return err == nil && f.IsDir(), err
 }
 
-// update will fetch origin and set HEAD to origin/HEAD
-// and also will create a pristine working directory by removing
-// untracked files and directories.
-func updateAndCleanUntracked(destinationPath string) error {
-	if _, err := Exec(destinationPath, "fetch", "-v"); err != nil {
-		return errors.Wrapf(err, "fetch index at %q failed", destinationPath)
-	}
-
-	if _, err := Exec(destinationPath, "reset", "--hard", "@{upstream}"); err != nil {
-		return errors.Wrapf(err, "reset index at %q failed", destinationPath)
-	}
-
-	_, err := Exec(destinationPath, "clean", "-xfd")
-	return errors.Wrapf(err, "clean index at %q failed", destinationPath)
-}
-
 // EnsureUpdated will ensure the destination path exists and is up to date.
 func EnsureUpdated(uri, destinationPath string) error {
+	return EnsureUpdatedContext(context.Background(), uri, destinationPath)
+}
+
+// EnsureUpdatedContext is like EnsureUpdated, but the fetch is aborted when
+// ctx is done.
+func EnsureUpdatedContext(ctx context.Context, uri, destinationPath string) error {
 	if err := EnsureCloned(uri, destinationPath); err != nil {
 		return err
 	}
-	return updateAndCleanUntracked(destinationPath)
+	if err := checkNetwork(uri, "fetching"); err != nil {
+		return err
+	}
+	return clientFor(uri).updateAndCleanUntracked(ctx, destinationPath)
 }
 
 // GetRemoteURL returns the url of the remote origin
 func GetRemoteURL(dir string) (string, error) {
-	return Exec(dir, "config", "--get", "remote.origin.url")
+	return newClient().getRemoteURL(dir)
+}
+
+// SetRemoteURL changes the url of the remote origin. It does not fetch from
+// the new url.
+func SetRemoteURL(dir, uri string) error {
+	return errors.Wrapf(newClient().setRemoteURL(dir, uri), "failed to set the remote of %q", dir)
+}
+
+// GetCommit returns the commit checked out in dir, or an empty string if the
+// repository has no commits yet.
+func GetCommit(dir string) (string, error) {
+	return newClient().getCommit(dir)
+}
+
+// GetStatus reports local changes to the clone at dir. It does not fetch, so
+// a clone that is behind its remote is not considered diverged.
+func GetStatus(dir string) (Status, error) {
+	s, err := newClient().status(dir)
+	return s, errors.Wrapf(err, "failed to get the status of %q", dir)
+}
+
+// checkNetwork returns an error if the operation on the repository at uri
+// needs network access in offline mode.
+func checkNetwork(uri, op string) error {
+	if isLocalURL(uri) {
+		return nil
+	}
+	return network.Check(fmt.Sprintf("%s %q", op, uri))
 }
 
-func Exec(pwd string, args ...string) (string, error) {
-	klog.V(4).Infof("Going to run git %s", strings.Join(args, " "))
-	cmd := osexec.Command("git", args...)
-	cmd.Dir = pwd
-	buf := bytes.Buffer{}
-	var w io.Writer = &buf
-	if klog.V(2).Enabled() {
-		w = io.MultiWriter(w, os.Stderr)
+// isLocalURL tells whether uri refers to a repository on the local file
+// system rather than a remote one.
+func isLocalURL(uri string) bool {
+	if strings.HasPrefix(uri, "file://") {
+		return true
+	}
+	if strings.Contains(uri, "://") {
+		return false
 	}
-	cmd.Stdout, cmd.Stderr = w, w
-	if err := cmd.Run(); err != nil {
-		return "", errors.Wrapf(err, "command execution failure, output=%q", buf.String())
+	// scp-like syntax [user@]host:path, not to be confused with a path that
+	// starts with a Windows drive letter
+	if i := strings.Index(uri, ":"); i > 1 && !strings.ContainsAny(uri[:i], `/\`) {
+		return false
 	}
-	return strings.TrimSpace(buf.String()), nil
+	return true
+}
+
+// isSSHURL tells whether uri refers to a repository that is accessed over
+// ssh, either with an ssh:// URL or with the scp-like syntax.
+func isSSHURL(uri string) bool {
+	lower := strings.ToLower(uri)
+	if strings.HasPrefix(lower, "ssh://") || strings.HasPrefix(lower, "git+ssh://") {
+		return true
+	}
+	return !strings.Contains(uri, "://") && !isLocalURL(uri)
+}
+
+func shallowClonesDisabled() bool {
+	_, disabled := os.LookupEnv(noShallowCloneEnv)
+	return disabled
 }
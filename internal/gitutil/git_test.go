@@ -0,0 +1,537 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitutil
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/krew/internal/network"
+)
+
+// newRemote creates a repository with a plugins directory and another
+// directory, and returns a function that commits changes to it.
+func newRemote(t *testing.T) (string, func(msg string)) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "krew-git-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	remote := filepath.Join(dir, "remote")
+	for _, d := range []string{"plugins", "docs"} {
+		if err := os.MkdirAll(filepath.Join(remote, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Exec(remote, "init"); err != nil {
+		t.Fatal(err)
+	}
+	commit := func(msg string) {
+		t.Helper()
+		for _, d := range []string{"plugins", "docs"} {
+			if err := ioutil.WriteFile(filepath.Join(remote, d, "file"), []byte(msg), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := Exec(remote, "add", "."); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Exec(remote, "-c", "user.name=krew", "-c", "user.email=krew@example.com", "commit", "-m", msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("first")
+	return remote, commit
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestEnsureCloned_shallowAndSparse(t *testing.T) {
+	os.Setenv(clientEnv, "exec")
+	defer os.Unsetenv(clientEnv)
+
+	remote, commit := newRemote(t)
+	commit("second")
+	dst := filepath.Join(filepath.Dir(remote), "clone")
+
+	if err := EnsureCloned("file://"+remote, dst); err != nil {
+		t.Fatal(err)
+	}
+	if shallow, err := IsShallow(dst); err != nil || !shallow {
+		t.Errorf("expected a shallow clone, got shallow=%v err=%v", shallow, err)
+	}
+	if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "second" {
+		t.Errorf("expected the latest plugins to be checked out, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "docs")); !os.IsNotExist(err) {
+		t.Errorf("expected directories other than plugins not to be checked out, got: %v", err)
+	}
+
+	commit("third")
+	if err := EnsureUpdated("file://"+remote, dst); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "third" {
+		t.Errorf("expected the update to check out the latest plugins, got %q", got)
+	}
+	if shallow, err := IsShallow(dst); err != nil || !shallow {
+		t.Errorf("expected the clone to stay shallow after update, got shallow=%v err=%v", shallow, err)
+	}
+}
+
+func TestEnsureClonedAtRef_unshallowsForOldCommits(t *testing.T) {
+	for _, name := range []string{"exec", "builtin"} {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(clientEnv, name)
+			defer os.Unsetenv(clientEnv)
+
+			remote, commit := newRemote(t)
+			first, err := Exec(remote, "rev-parse", "HEAD")
+			if err != nil {
+				t.Fatal(err)
+			}
+			commit("second")
+			// disallow fetching commits by id so that the history has to be fetched
+			if _, err := Exec(remote, "config", "uploadpack.allowAnySHA1InWant", "false"); err != nil {
+				t.Fatal(err)
+			}
+			dst := filepath.Join(filepath.Dir(remote), "clone")
+
+			if err := EnsureClonedAtRef("file://"+remote, dst, first[:7]); err != nil {
+				t.Fatal(err)
+			}
+			if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "first" {
+				t.Errorf("expected the pinned commit to be checked out, got %q", got)
+			}
+			if ref, err := GetRef(dst); err != nil || ref != first[:7] {
+				t.Errorf("GetRef() = %q, %v; want %q", ref, err, first[:7])
+			}
+			if shallow, err := IsShallow(dst); err != nil || shallow {
+				t.Errorf("expected the history to be fetched, got shallow=%v err=%v", shallow, err)
+			}
+
+			commit("third")
+			if err := EnsureUpdated("file://"+remote, dst); err != nil {
+				t.Fatal(err)
+			}
+			if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "first" {
+				t.Errorf("expected the index to stay at the pinned commit after update, got %q", got)
+			}
+		})
+	}
+}
+
+func Test_fetchHistory(t *testing.T) {
+	remote, commit := newRemote(t)
+	commit("second")
+	for name, c := range map[string]client{"exec": execClient{}, "builtin": builtinClient{}} {
+		t.Run(name, func(t *testing.T) {
+			dst := filepath.Join(filepath.Dir(remote), name)
+			if err := c.ensureClonedAtRef("file://"+remote, dst, ""); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := c.setRemoteURL(dst, "https://example.com/index.git"); err != nil {
+				t.Fatal(err)
+			}
+			network.SetOffline(true)
+			err := fetchHistory(context.Background(), c, dst)
+			network.SetOffline(false)
+			if err == nil {
+				t.Error("expected fetching the history from a remote to fail in offline mode")
+			}
+			if err := c.setRemoteURL(dst, "file://"+remote); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := fetchHistory(context.Background(), c, dst); err != nil {
+				t.Fatal(err)
+			}
+			if shallow, err := c.isShallow(dst); err != nil || shallow {
+				t.Errorf("expected the history to be fetched, got shallow=%v err=%v", shallow, err)
+			}
+			if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "second" {
+				t.Errorf("expected the latest plugins to stay checked out, got %q", got)
+			}
+			if _, err := os.Stat(filepath.Join(dst, "docs")); !os.IsNotExist(err) {
+				t.Errorf("expected the sparse checkout to be kept, got: %v", err)
+			}
+			// no-op for complete clones
+			if err := fetchHistory(context.Background(), c, dst); err != nil {
+				t.Errorf("fetchHistory() of a complete clone failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestEnsureCloned_noShallowClone(t *testing.T) {
+	os.Setenv(clientEnv, "exec")
+	defer os.Unsetenv(clientEnv)
+	os.Setenv(noShallowCloneEnv, "1")
+	defer os.Unsetenv(noShallowCloneEnv)
+
+	remote, commit := newRemote(t)
+	commit("second")
+	dst := filepath.Join(filepath.Dir(remote), "clone")
+
+	if err := EnsureCloned("file://"+remote, dst); err != nil {
+		t.Fatal(err)
+	}
+	if shallow, err := IsShallow(dst); err != nil || shallow {
+		t.Errorf("expected a full clone, got shallow=%v err=%v", shallow, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "docs", "file")); err != nil {
+		t.Errorf("expected all files to be checked out: %v", err)
+	}
+}
+
+func TestBuiltinClient(t *testing.T) {
+	os.Setenv(clientEnv, "builtin")
+	defer os.Unsetenv(clientEnv)
+
+	remote, commit := newRemote(t)
+	if _, err := Exec(remote, "tag", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	commit("second")
+	url := "file://" + remote
+
+	dst := filepath.Join(filepath.Dir(remote), "clone")
+	if err := EnsureCloned(url, dst); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := GetRemoteURL(dst); err != nil || got != url {
+		t.Errorf("GetRemoteURL() = %q, %v; want %q", got, err, url)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dst, "plugins", "untracked"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	commit("third")
+	if err := EnsureUpdated(url, dst); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "third" {
+		t.Errorf("expected the update to check out the latest plugins, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "plugins", "untracked")); !os.IsNotExist(err) {
+		t.Errorf("expected untracked files to be removed, got: %v", err)
+	}
+
+	pinned := filepath.Join(filepath.Dir(remote), "pinned")
+	if err := EnsureClonedAtRef(url, pinned, "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(pinned, "plugins", "file")); got != "first" {
+		t.Errorf("expected the pinned tag to be checked out, got %q", got)
+	}
+	if ref, err := GetRef(pinned); err != nil || ref != "v1" {
+		t.Errorf("GetRef() = %q, %v; want %q", ref, err, "v1")
+	}
+	commit("fourth")
+	if err := EnsureUpdated(url, pinned); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(pinned, "plugins", "file")); got != "first" {
+		t.Errorf("expected the index to stay at the pinned tag after update, got %q", got)
+	}
+}
+
+func TestBuiltinClient_shallowAndSparse(t *testing.T) {
+	os.Setenv(clientEnv, "builtin")
+	defer os.Unsetenv(clientEnv)
+
+	remote, commit := newRemote(t)
+	commit("second")
+	url := "file://" + remote
+	dst := filepath.Join(filepath.Dir(remote), "clone")
+
+	if err := EnsureCloned(url, dst); err != nil {
+		t.Fatal(err)
+	}
+	if shallow, err := IsShallow(dst); err != nil || !shallow {
+		t.Errorf("expected a shallow clone, got shallow=%v err=%v", shallow, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "docs")); !os.IsNotExist(err) {
+		t.Errorf("expected directories other than plugins not to be checked out, got: %v", err)
+	}
+
+	commit("third")
+	if err := EnsureUpdated(url, dst); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "third" {
+		t.Errorf("expected the update to check out the latest plugins, got %q", got)
+	}
+	if shallow, err := IsShallow(dst); err != nil || !shallow {
+		t.Errorf("expected the clone to stay shallow after update, got shallow=%v err=%v", shallow, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "docs")); !os.IsNotExist(err) {
+		t.Errorf("expected the update to keep the sparse checkout, got: %v", err)
+	}
+
+	// the git binary can update the clone as well
+	commit("fourth")
+	if err := (execClient{}).updateAndCleanUntracked(context.Background(), dst); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "fourth" {
+		t.Errorf("expected the git binary to check out the latest plugins, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "docs")); !os.IsNotExist(err) {
+		t.Errorf("expected the git binary to keep the sparse checkout, got: %v", err)
+	}
+}
+
+func TestBuiltinClient_updatesShallowCloneOfGitBinary(t *testing.T) {
+	remote, commit := newRemote(t)
+	commit("second")
+	url := "file://" + remote
+	dst := filepath.Join(filepath.Dir(remote), "clone")
+	if err := (execClient{}).ensureClonedAtRef(url, dst, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	commit("third")
+	if err := (builtinClient{}).updateAndCleanUntracked(context.Background(), dst); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "third" {
+		t.Errorf("expected the update to check out the latest plugins, got %q", got)
+	}
+	if shallow, err := IsShallow(dst); err != nil || !shallow {
+		t.Errorf("expected the clone to stay shallow after update, got shallow=%v err=%v", shallow, err)
+	}
+}
+
+func TestBuiltinClient_failedUpdateKeepsClone(t *testing.T) {
+	remote, commit := newRemote(t)
+	url := "file://" + remote
+	dst := filepath.Join(filepath.Dir(remote), "clone")
+	c := builtinClient{}
+	if err := c.ensureClonedAtRef(url, dst, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	commit("second")
+	if err := os.RemoveAll(remote); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.unshallow(context.Background(), dst); err == nil {
+		t.Fatal("expected cloning a removed remote to fail")
+	}
+	if err := c.updateAndCleanUntracked(context.Background(), dst); err == nil {
+		t.Fatal("expected updating from a removed remote to fail")
+	}
+	if got := readFile(t, filepath.Join(dst, "plugins", "file")); got != "first" {
+		t.Errorf("expected the clone to be kept, got %q", got)
+	}
+	entries, err := ioutil.ReadDir(filepath.Dir(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected temporary clones to be removed, got %d entries", len(entries))
+	}
+}
+
+func TestBuiltinClient_emptyRemote(t *testing.T) {
+	os.Setenv(clientEnv, "builtin")
+	defer os.Unsetenv(clientEnv)
+
+	dir, err := ioutil.TempDir("", "krew-git-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	remote := filepath.Join(dir, "remote")
+	if _, err := Exec("", "init", remote); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "clone")
+	if err := EnsureCloned(remote, dst); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := GetRemoteURL(dst); err != nil || got != remote {
+		t.Errorf("GetRemoteURL() = %q, %v; want %q", got, err, remote)
+	}
+	if got, err := GetCommit(dst); err != nil || got != "" {
+		t.Errorf("GetCommit() of empty clone = %q, %v; want empty", got, err)
+	}
+}
+
+func TestGetCommit(t *testing.T) {
+	remote, commit := newRemote(t)
+	commit("second")
+	want, err := Exec(remote, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, c := range map[string]client{"exec": execClient{}, "builtin": builtinClient{}} {
+		dst := filepath.Join(filepath.Dir(remote), name)
+		if err := c.ensureClonedAtRef("file://"+remote, dst, ""); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := c.getCommit(dst); err != nil || got != want {
+			t.Errorf("%s: getCommit() = %q, %v; want %q", name, got, err, want)
+		}
+	}
+}
+
+func TestSetRemoteURL(t *testing.T) {
+	remote, _ := newRemote(t)
+	for name, c := range map[string]client{"exec": execClient{}, "builtin": builtinClient{}} {
+		dst := filepath.Join(filepath.Dir(remote), name)
+		if err := c.ensureClonedAtRef("file://"+remote, dst, ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.setRemoteURL(dst, "https://example.com/mirror.git"); err != nil {
+			t.Fatalf("%s: setRemoteURL() error = %v", name, err)
+		}
+		if got, err := c.getRemoteURL(dst); err != nil || got != "https://example.com/mirror.git" {
+			t.Errorf("%s: getRemoteURL() = %q, %v", name, got, err)
+		}
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	remote, _ := newRemote(t)
+	for name, c := range map[string]client{"exec": execClient{}, "builtin": builtinClient{}} {
+		dst := filepath.Join(filepath.Dir(remote), name)
+		if err := c.ensureClonedAtRef("file://"+remote, dst, ""); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := c.status(dst); err != nil || got != (Status{}) {
+			t.Errorf("%s: status() of a fresh clone = %+v, %v", name, got, err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dst, "plugins", "file"), []byte("changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := c.status(dst); err != nil || got != (Status{Dirty: true}) {
+			t.Errorf("%s: status() with a modified file = %+v, %v", name, got, err)
+		}
+
+		if _, err := Exec(dst, "-c", "user.name=krew", "-c", "user.email=krew@example.com", "commit", "-am", "local"); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := c.status(dst); err != nil || got != (Status{Diverged: true}) {
+			t.Errorf("%s: status() with a local commit = %+v, %v", name, got, err)
+		}
+	}
+}
+
+func TestEnsureUpdatedContext_canceled(t *testing.T) {
+	remote, commit := newRemote(t)
+	url := "file://" + remote
+	dst := filepath.Join(filepath.Dir(remote), "clone")
+	if err := EnsureCloned(url, dst); err != nil {
+		t.Fatal(err)
+	}
+	before, err := GetCommit(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit("second")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := EnsureUpdatedContext(ctx, url, dst); err == nil {
+		t.Error("expected update with a canceled context to fail")
+	}
+	if after, err := GetCommit(dst); err != nil || after != before {
+		t.Errorf("expected clone to stay at %q, got %q, %v", before, after, err)
+	}
+}
+
+func Test_newClient(t *testing.T) {
+	defer os.Unsetenv(clientEnv)
+	os.Unsetenv(clientEnv)
+	if _, ok := newClient().(builtinClient); !ok {
+		t.Errorf("expected the built-in client by default")
+	}
+	os.Setenv(clientEnv, "builtin")
+	if _, ok := newClient().(builtinClient); !ok {
+		t.Errorf("expected the built-in client with %s=builtin", clientEnv)
+	}
+	os.Setenv(clientEnv, "exec")
+	if _, ok := newClient().(execClient); !ok {
+		t.Errorf("expected the exec client with %s=exec", clientEnv)
+	}
+}
+
+func Test_isSSHURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://github.com/kubernetes-sigs/krew-index.git": false,
+		"ssh://git@example.com/index.git":                   true,
+		"git@github.com:foo/custom-index.git":               true,
+		"file:///tmp/index":                                 false,
+		"/tmp/index":                                        false,
+		`C:\index`:                                          false,
+	}
+	for uri, want := range tests {
+		if got := isSSHURL(uri); got != want {
+			t.Errorf("isSSHURL(%q) = %v, want %v", uri, got, want)
+		}
+	}
+}
+
+func Test_isLocalURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://github.com/kubernetes-sigs/krew-index.git": false,
+		"ssh://git@example.com/index.git":                   false,
+		"git@github.com:foo/custom-index.git":               false,
+		"file:///tmp/index":                                 true,
+		"/tmp/index":                                        true,
+		"../index":                                          true,
+		`C:\index`:                                          true,
+	}
+	for uri, want := range tests {
+		if got := isLocalURL(uri); got != want {
+			t.Errorf("isLocalURL(%q) = %v, want %v", uri, got, want)
+		}
+	}
+}
+
+func TestEnsureCloned_offline(t *testing.T) {
+	network.SetOffline(true)
+	defer network.SetOffline(false)
+
+	dir, err := ioutil.TempDir("", "krew-git-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := EnsureCloned("https://example.com/index.git", filepath.Join(dir, "remote")); err == nil {
+		t.Error("expected cloning a remote repository to fail in offline mode")
+	}
+
+	remote, _ := newRemote(t)
+	if err := EnsureCloned(remote, filepath.Join(dir, "local")); err != nil {
+		t.Errorf("expected cloning a local repository to work in offline mode, got: %v", err)
+	}
+}
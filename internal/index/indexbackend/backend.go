@@ -0,0 +1,163 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package indexbackend maintains the local copies of plugin indexes. An index
+// can be a git repository, an archive served over HTTP(S) or a directory on
+// the local file system.
+package indexbackend
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/krew/internal/gitutil"
+)
+
+// Types of index backends.
+const (
+	TypeGit   = "git"
+	TypeHTTP  = "http"
+	TypeLocal = "local"
+)
+
+// metadataFile stores the source of an index that is not a git repository,
+// relative to the directory of the local copy.
+const metadataFile = ".krew-index.yaml"
+
+// Backend creates and updates the local copy of a plugin index.
+type Backend interface {
+	// Type returns the type of the backend.
+	Type() string
+	// Add creates the local copy of the index at url in dir. If ref is not
+	// empty, the index is pinned to that ref.
+	Add(url, dir, ref string) error
+	// Update synchronizes the local copy in dir with its source. The update
+	// is aborted when ctx is done.
+	Update(ctx context.Context, dir string) error
+	// URL returns the source of the local copy in dir.
+	URL(dir string) (string, error)
+	// SetURL changes the source of the local copy in dir to url and
+	// synchronizes it with the new source. The source is not changed if the
+	// synchronization fails.
+	SetURL(ctx context.Context, dir, url string) error
+	// Ref returns the ref that the local copy in dir is pinned to, or an empty
+	// string if it is not pinned.
+	Ref(dir string) (string, error)
+	// Revision identifies the version of the local copy in dir, such as a
+	// commit. It is empty if the backend does not track versions.
+	Revision(dir string) (string, error)
+	// Status reports local changes to the local copy in dir. Backends that
+	// do not track changes report none.
+	Status(dir string) (Status, error)
+}
+
+// Status describes local changes to the local copy of an index.
+type Status struct {
+	// Dirty is set if files in the local copy were changed.
+	Dirty bool
+	// Diverged is set if the local copy is not at the revision of its ref.
+	Diverged bool
+}
+
+// metadata describes the source of an index that is not a git repository.
+type metadata struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	ETag string `json:"etag,omitempty"`
+}
+
+// ForURL returns the backend for an index at the given url. HTTP(S) URLs of
+// .tar.gz, .tgz or .zip archives use the HTTP backend, file:// URLs of
+// directories that have a plugins directory but are not git clones use the
+// local backend, and all other URLs (including bare repositories) are git
+// remotes.
+func ForURL(url string) Backend {
+	lower := strings.ToLower(url)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+			if strings.HasSuffix(lower, ext) {
+				return httpBackend{}
+			}
+		}
+		return gitBackend{}
+	}
+	if strings.HasPrefix(lower, "file://") {
+		path := localPath(url)
+		fi, err := os.Stat(filepath.Join(path, "plugins"))
+		if ok, gitErr := gitutil.IsGitCloned(path); err == nil && fi.IsDir() && gitErr == nil && !ok {
+			return localBackend{}
+		}
+	}
+	return gitBackend{}
+}
+
+// ForDir returns the backend that created the local copy of an index in dir.
+// It returns an error that can be tested with os.IsNotExist if dir is not
+// the local copy of an index.
+func ForDir(dir string) (Backend, error) {
+	if ok, err := gitutil.IsGitCloned(dir); err != nil {
+		return nil, err
+	} else if ok {
+		return gitBackend{}, nil
+	}
+	m, err := readMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+	switch m.Type {
+	case TypeHTTP:
+		return httpBackend{}, nil
+	case TypeLocal:
+		return localBackend{}, nil
+	default:
+		return nil, errors.Errorf("unknown index type %q in %q", m.Type, dir)
+	}
+}
+
+// Exists tells whether dir contains the local copy of an index.
+func Exists(dir string) (bool, error) {
+	_, err := ForDir(dir)
+	if os.IsNotExist(errors.Cause(err)) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func readMetadata(dir string) (metadata, error) {
+	var m metadata
+	b, err := ioutil.ReadFile(filepath.Join(dir, metadataFile))
+	if err != nil {
+		return m, err
+	}
+	return m, errors.Wrapf(yaml.Unmarshal(b, &m), "failed to parse index metadata in %q", dir)
+}
+
+func writeMetadata(dir string, m metadata) error {
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert index metadata to yaml")
+	}
+	return errors.Wrapf(ioutil.WriteFile(filepath.Join(dir, metadataFile), b, 0644),
+		"failed to write index metadata in %q", dir)
+}
+
+func errRefNotSupported(typ string) error {
+	return errors.Errorf("indexes of type %q cannot be pinned to a ref", typ)
+}
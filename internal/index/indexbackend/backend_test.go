@@ -0,0 +1,247 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexbackend
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/krew/internal/gitutil"
+	"sigs.k8s.io/krew/internal/testutil"
+)
+
+func TestForURL(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("plain/plugins/foo.yaml", nil)
+	tmpDir.InitEmptyGitRepo(tmpDir.Path("repo"), "")
+	if _, err := gitutil.Exec("", "init", "--bare", tmpDir.Path("bare")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://github.com/kubernetes-sigs/krew-index.git", want: TypeGit},
+		{url: "git@github.com:foo/custom-index.git", want: TypeGit},
+		{url: "https://example.com/index.tar.gz", want: TypeHTTP},
+		{url: "HTTP://example.com/index.TGZ", want: TypeHTTP},
+		{url: "https://example.com/index.zip", want: TypeHTTP},
+		{url: "file://" + filepath.ToSlash(tmpDir.Path("plain")), want: TypeLocal},
+		{url: "file://" + filepath.ToSlash(tmpDir.Path("repo")), want: TypeGit},
+		{url: "file://" + filepath.ToSlash(tmpDir.Path("bare")), want: TypeGit},
+		{url: tmpDir.Path("plain"), want: TypeGit},
+	}
+	for _, tt := range tests {
+		if got := ForURL(tt.url).Type(); got != tt.want {
+			t.Errorf("ForURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestHTTPBackend(t *testing.T) {
+	snapshot := tarGz(t, map[string]string{"krew-index-master/plugins/foo.yaml": "v1"})
+	etag := `"1"`
+	var downloads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write(snapshot)
+	}))
+	defer server.Close()
+
+	tmpDir := testutil.NewTempDir(t)
+	dir := tmpDir.Path("index/foo")
+	url := server.URL + "/index.tar.gz"
+
+	b := ForURL(url)
+	if err := b.Add(url, dir, "v1"); err == nil {
+		t.Error("expected error when pinning an HTTP index to a ref")
+	}
+	if err := b.Add(url, dir, ""); err != nil {
+		t.Fatal(err)
+	}
+	assertFile(t, filepath.Join(dir, "plugins", "foo.yaml"), "v1")
+
+	got, err := ForDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type() != TypeHTTP {
+		t.Fatalf("ForDir() returned backend of type %q", got.Type())
+	}
+	if u, err := got.URL(dir); err != nil || u != url {
+		t.Errorf("URL() = %q, %v; want %q", u, err, url)
+	}
+
+	if err := got.Update(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+	if downloads != 1 {
+		t.Errorf("expected unchanged snapshot not to be downloaded again, got %d downloads", downloads)
+	}
+	if rev, err := got.Revision(dir); err != nil || rev != etag {
+		t.Errorf("Revision() = %q, %v; want %q", rev, err, etag)
+	}
+
+	snapshot = tarGz(t, map[string]string{"plugins/bar.yaml": "v2"})
+	etag = `"2"`
+	if err := got.Update(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+	assertFile(t, filepath.Join(dir, "plugins", "bar.yaml"), "v2")
+	if _, err := os.Stat(filepath.Join(dir, "plugins", "foo.yaml")); !os.IsNotExist(err) {
+		t.Errorf("expected plugins removed from the snapshot to be deleted, got: %v", err)
+	}
+
+	mirror := server.URL + "/mirror.tar.gz"
+	if err := got.SetURL(context.Background(), dir, mirror); err != nil {
+		t.Fatal(err)
+	}
+	if downloads != 3 {
+		t.Errorf("expected snapshot to be downloaded from the new URL, got %d downloads", downloads)
+	}
+	if u, err := got.URL(dir); err != nil || u != mirror {
+		t.Errorf("URL() = %q, %v; want %q", u, err, mirror)
+	}
+}
+
+func TestLocalBackend(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("monorepo/plugins/foo.yaml", []byte("v1"))
+	dir := tmpDir.Path("index/foo")
+	url := "file://" + filepath.ToSlash(tmpDir.Path("monorepo"))
+
+	if err := (localBackend{}).Add("file://"+filepath.ToSlash(tmpDir.Path("missing")), dir, ""); err == nil {
+		t.Error("expected error when adding a directory without plugins")
+	}
+	if err := ForURL(url).Add(url, dir, ""); err != nil {
+		t.Fatal(err)
+	}
+	tmpDir.Write("monorepo/plugins/foo.yaml", []byte("v2"))
+	assertFile(t, filepath.Join(dir, "plugins", "foo.yaml"), "v2")
+
+	b, err := ForDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Type() != TypeLocal {
+		t.Fatalf("ForDir() returned backend of type %q", b.Type())
+	}
+	if u, err := b.URL(dir); err != nil || u != url {
+		t.Errorf("URL() = %q, %v; want %q", u, err, url)
+	}
+	if err := b.Update(context.Background(), dir); err != nil {
+		t.Errorf("Update() error = %v", err)
+	}
+
+	if err := b.SetURL(context.Background(), dir, "file://"+filepath.ToSlash(tmpDir.Path("missing"))); err == nil {
+		t.Error("expected error when setting the URL to a directory without plugins")
+	}
+	assertFile(t, filepath.Join(dir, "plugins", "foo.yaml"), "v2")
+	tmpDir.Write("file/plugins", []byte("not a directory"))
+	if err := b.SetURL(context.Background(), dir, "file://"+filepath.ToSlash(tmpDir.Path("file"))); err == nil {
+		t.Error("expected error when setting the URL to a directory whose plugins is a file")
+	}
+	assertFile(t, filepath.Join(dir, "plugins", "foo.yaml"), "v2")
+	if u, err := b.URL(dir); err != nil || u != url {
+		t.Errorf("URL() after failed SetURL() = %q, %v; want %q", u, err, url)
+	}
+	tmpDir.Write("mirror/plugins/foo.yaml", []byte("mirror"))
+	mirror := "file://" + filepath.ToSlash(tmpDir.Path("mirror"))
+	if err := b.SetURL(context.Background(), dir, mirror); err != nil {
+		t.Fatal(err)
+	}
+	assertFile(t, filepath.Join(dir, "plugins", "foo.yaml"), "mirror")
+	if u, err := b.URL(dir); err != nil || u != mirror {
+		t.Errorf("URL() = %q, %v; want %q", u, err, mirror)
+	}
+	if entries, err := ioutil.ReadDir(dir); err != nil || len(entries) != 2 {
+		t.Errorf("expected only the link and the metadata in %q, got %d entries, %v", dir, len(entries), err)
+	}
+}
+
+func Test_localPath(t *testing.T) {
+	tests := map[string]string{
+		"file:///tmp/index":            "/tmp/index",
+		"file://localhost/tmp/index":   "/tmp/index",
+		"file:///C:/index":             "C:/index",
+		"file://C:/index":              "C:/index",
+		"file:///tmp/my%20index":       "/tmp/my index",
+		"file://relative/dir/index":    "relative/dir/index",
+		"file:///C:/Users/me/index%23": "C:/Users/me/index#",
+	}
+	for in, want := range tests {
+		if got := localPath(in); got != filepath.FromSlash(want) {
+			t.Errorf("localPath(%q) = %q, want %q", in, got, filepath.FromSlash(want))
+		}
+	}
+}
+
+func TestExists(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	if ok, err := Exists(tmpDir.Path("missing")); err != nil || ok {
+		t.Errorf("Exists() on missing dir = %v, %v", ok, err)
+	}
+	tmpDir.InitEmptyGitRepo(tmpDir.Path("repo"), "")
+	if ok, err := Exists(tmpDir.Path("repo")); err != nil || !ok {
+		t.Errorf("Exists() on git repo = %v, %v", ok, err)
+	}
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != want {
+		t.Errorf("content of %q = %q, want %q", path, b, want)
+	}
+}
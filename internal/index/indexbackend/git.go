@@ -0,0 +1,69 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexbackend
+
+import (
+	"context"
+
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/internal/gitutil"
+)
+
+// gitBackend keeps a clone of a git repository.
+type gitBackend struct{}
+
+func (gitBackend) Type() string { return TypeGit }
+
+func (gitBackend) Add(url, dir, ref string) error {
+	return gitutil.EnsureClonedAtRef(url, dir, ref)
+}
+
+func (b gitBackend) Update(ctx context.Context, dir string) error {
+	url, err := b.URL(dir)
+	if err != nil {
+		return err
+	}
+	return gitutil.EnsureUpdatedContext(ctx, url, dir)
+}
+
+func (gitBackend) URL(dir string) (string, error) { return gitutil.GetRemoteURL(dir) }
+
+func (b gitBackend) SetURL(ctx context.Context, dir, url string) error {
+	old, err := b.URL(dir)
+	if err != nil {
+		return err
+	}
+	if err := gitutil.SetRemoteURL(dir, url); err != nil {
+		return err
+	}
+	if err := gitutil.EnsureUpdatedContext(ctx, url, dir); err != nil {
+		if resetErr := gitutil.SetRemoteURL(dir, old); resetErr != nil {
+			klog.Warningf("failed to restore the remote of %q: %v", dir, resetErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (gitBackend) Ref(dir string) (string, error) { return gitutil.GetRef(dir) }
+
+// Revision returns the commit checked out in dir.
+func (gitBackend) Revision(dir string) (string, error) { return gitutil.GetCommit(dir) }
+
+func (gitBackend) Status(dir string) (Status, error) {
+	s, err := gitutil.GetStatus(dir)
+	return Status{Dirty: s.Dirty, Diverged: s.Diverged}, err
+}
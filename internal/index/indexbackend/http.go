@@ -0,0 +1,159 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexbackend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/internal/download"
+	"sigs.k8s.io/krew/internal/network"
+)
+
+// httpBackend downloads a snapshot of an index as a .tar.gz or .zip archive.
+// The archive must contain the plugins directory at its root or in a single
+// top-level directory. The snapshot is only downloaded again if its ETag
+// changes.
+type httpBackend struct{}
+
+func (httpBackend) Type() string { return TypeHTTP }
+
+func (b httpBackend) Add(url, dir, ref string) error {
+	if ref != "" {
+		return errRefNotSupported(TypeHTTP)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory %q", dir)
+	}
+	return b.sync(context.Background(), dir, metadata{Type: TypeHTTP, URL: url})
+}
+
+func (b httpBackend) Update(ctx context.Context, dir string) error {
+	m, err := readMetadata(dir)
+	if err != nil {
+		return err
+	}
+	return b.sync(ctx, dir, m)
+}
+
+func (httpBackend) URL(dir string) (string, error) {
+	m, err := readMetadata(dir)
+	return m.URL, err
+}
+
+// SetURL downloads the snapshot at url. The metadata is only updated if the
+// download succeeds.
+func (b httpBackend) SetURL(ctx context.Context, dir, url string) error {
+	m, err := readMetadata(dir)
+	if err != nil {
+		return err
+	}
+	m.URL, m.ETag = url, ""
+	return b.sync(ctx, dir, m)
+}
+
+func (httpBackend) Ref(string) (string, error) { return "", nil }
+
+// Revision returns the ETag of the downloaded snapshot.
+func (httpBackend) Revision(dir string) (string, error) {
+	m, err := readMetadata(dir)
+	return m.ETag, err
+}
+
+func (httpBackend) Status(string) (Status, error) { return Status{}, nil }
+
+// sync downloads the snapshot described by m unless its ETag is unchanged,
+// and replaces the plugins directory in dir with the one in the snapshot.
+func (httpBackend) sync(ctx context.Context, dir string, m metadata) error {
+	if err := network.Check(fmt.Sprintf("downloading index snapshot %q", m.URL)); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.URL, nil)
+	if err != nil {
+		return errors.Wrapf(err, "invalid index URL %q", m.URL)
+	}
+	if m.ETag != "" {
+		req.Header.Set("If-None-Match", m.ETag)
+	}
+	klog.V(2).Infof("Fetching index snapshot %q", m.URL)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to download %q", m.URL)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		klog.V(2).Infof("Index snapshot %q is not modified", m.URL)
+		return nil
+	case http.StatusOK:
+	default:
+		return errors.Errorf("failed to download %q: %s", m.URL, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %q", m.URL)
+	}
+
+	staging, err := ioutil.TempDir(dir, ".staging-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create staging directory")
+	}
+	defer os.RemoveAll(staging)
+	if err := download.ExtractArchive(staging, bytes.NewReader(data), int64(len(data))); err != nil {
+		return errors.Wrapf(err, "failed to extract index snapshot %q", m.URL)
+	}
+	src, err := findPluginsDir(staging)
+	if err != nil {
+		return err
+	}
+
+	dst := filepath.Join(dir, "plugins")
+	if err := os.RemoveAll(dst); err != nil {
+		return errors.Wrap(err, "failed to remove old plugins directory")
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return errors.Wrap(err, "failed to move plugins directory")
+	}
+	m.ETag = resp.Header.Get("ETag")
+	return writeMetadata(dir, m)
+}
+
+// findPluginsDir returns the plugins directory at the root of an extracted
+// archive, or in its only top-level directory.
+func findPluginsDir(root string) (string, error) {
+	if fi, err := os.Stat(filepath.Join(root, "plugins")); err == nil && fi.IsDir() {
+		return filepath.Join(root, "plugins"), nil
+	}
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read extracted archive")
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		p := filepath.Join(root, entries[0].Name(), "plugins")
+		if fi, err := os.Stat(p); err == nil && fi.IsDir() {
+			return p, nil
+		}
+	}
+	return "", errors.New("index snapshot does not contain a plugins directory")
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexbackend
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// localBackend links the plugins directory of a directory on the local file
+// system, so that changes to the directory are visible without an update.
+type localBackend struct{}
+
+func (localBackend) Type() string { return TypeLocal }
+
+func (b localBackend) Add(url, dir, ref string) error {
+	if ref != "" {
+		return errRefNotSupported(TypeLocal)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory %q", dir)
+	}
+	if err := b.link(url, dir); err != nil {
+		return err
+	}
+	return writeMetadata(dir, metadata{Type: TypeLocal, URL: url})
+}
+
+// link points the plugins directory in dir to the plugins directory of the
+// directory at url. An existing link is replaced in a single rename, so that
+// it is kept if linking fails.
+func (localBackend) link(url, dir string) error {
+	src, err := filepath.Abs(filepath.Join(localPath(url), "plugins"))
+	if err != nil {
+		return err
+	}
+	if fi, err := os.Stat(src); err != nil {
+		return errors.Wrapf(err, "failed to find the plugins directory of index %q", url)
+	} else if !fi.IsDir() {
+		return errors.Errorf("%q is not a directory", src)
+	}
+
+	dst := filepath.Join(dir, "plugins")
+	klog.V(2).Infof("Linking %q to %q", dst, src)
+	return errors.Wrap(replaceLink(src, dst), "failed to link the plugins directory")
+}
+
+// replaceLink creates a symbolic link to target at path under a temporary
+// name and renames it to path, replacing an existing link.
+func replaceLink(target, path string) error {
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+"-"+strconv.Itoa(os.Getpid()))
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Update checks that the linked directory still exists. The local copy does
+// not need to be synchronized.
+func (localBackend) Update(_ context.Context, dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, "plugins")); err != nil {
+		return errors.Wrap(err, "failed to find the plugins directory of the index")
+	}
+	return nil
+}
+
+func (localBackend) URL(dir string) (string, error) {
+	m, err := readMetadata(dir)
+	return m.URL, err
+}
+
+// SetURL links the plugins directory of the directory at url instead. The
+// old link is kept if the directory at url has no plugins directory.
+func (b localBackend) SetURL(_ context.Context, dir, url string) error {
+	old, err := os.Readlink(filepath.Join(dir, "plugins"))
+	if err != nil {
+		return errors.Wrap(err, "failed to read the link to the plugins directory")
+	}
+	if err := b.link(url, dir); err != nil {
+		return err
+	}
+	if err := writeMetadata(dir, metadata{Type: TypeLocal, URL: url}); err != nil {
+		if restoreErr := replaceLink(old, filepath.Join(dir, "plugins")); restoreErr != nil {
+			klog.Warningf("failed to restore the link to %q: %v", old, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (localBackend) Ref(string) (string, error) { return "", nil }
+
+// Revision is always empty, the local copy is the source itself.
+func (localBackend) Revision(string) (string, error) { return "", nil }
+
+func (localBackend) Status(string) (Status, error) { return Status{}, nil }
+
+// localPath returns the path of a file:// URL. A drive letter in the URL,
+// as in file:///C:/index, starts the path, and a host other than localhost
+// is the server of a UNC path on Windows.
+func localPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return filepath.FromSlash(strings.TrimPrefix(rawURL, "file://"))
+	}
+	p := u.Path
+	switch {
+	case u.Host == "" || u.Host == "localhost":
+	case isDrive(u.Host):
+		p = u.Host + p
+	case runtime.GOOS == "windows":
+		p = "//" + u.Host + p
+	default:
+		// file://dir/index is the relative path dir/index
+		p = u.Host + p
+	}
+	if len(p) >= 3 && p[0] == '/' && isDrive(p[1:3]) {
+		p = p[1:]
+	}
+	return filepath.FromSlash(p)
+}
+
+// isDrive tells whether s is a Windows drive letter followed by a colon.
+func isDrive(s string) bool {
+	return len(s) == 2 && s[1] == ':' &&
+		('a' <= s[0] && s[0] <= 'z' || 'A' <= s[0] && s[0] <= 'Z')
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package indexcache stores the parsed plugin list of an index, so that the
+// manifests only have to be read and validated again when the index changes.
+package indexcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/internal/index/indexscanner"
+	"sigs.k8s.io/krew/pkg/constants"
+	"sigs.k8s.io/krew/pkg/index"
+)
+
+// version is incremented when the format of the cache file changes, so that
+// caches written by other krew versions are not used.
+const version = 3
+
+// List is the parsed plugin list of an index.
+type List struct {
+	Plugins []index.Plugin `json:"plugins"`
+	// Failed are the names of the plugins whose manifests could not be read
+	// or parsed.
+	Failed []string `json:"failed,omitempty"`
+}
+
+type cacheFile struct {
+	Version int    `json:"version"`
+	Key     string `json:"key"`
+	List
+}
+
+// Load returns the plugins in pluginsDir. They are read from the cache file at
+// path if it was written for the same revision of the index, and otherwise
+// loaded from pluginsDir and stored in the cache file. Indexes without a
+// revision are identified by the names and modification times of their
+// manifests.
+func Load(path, pluginsDir, revision string) (List, error) {
+	key, err := cacheKey(pluginsDir, revision)
+	if err != nil {
+		return List{}, err
+	}
+	if l, ok := read(path, key); ok {
+		klog.V(4).Infof("Loaded %d plugins from cache %q", len(l.Plugins), path)
+		return l, nil
+	}
+
+	plugins, failed, err := indexscanner.LoadPluginListWithFailures(pluginsDir)
+	if err != nil {
+		return List{}, err
+	}
+	l := List{Plugins: plugins, Failed: failed}
+	// The cache only makes loading faster, so failing to write it is not an
+	// error.
+	if err := write(path, cacheFile{Version: version, Key: key, List: l}); err != nil {
+		klog.V(1).Infof("WARNING: failed to write plugin list cache: %v", err)
+	}
+	return l, nil
+}
+
+// Invalidate removes the cache file at path. It is not an error if the file
+// does not exist.
+func Invalidate(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove plugin list cache %q", path)
+	}
+	return nil
+}
+
+// cacheKey identifies the contents of pluginsDir.
+func cacheKey(pluginsDir, revision string) (string, error) {
+	if revision != "" {
+		return "revision:" + revision, nil
+	}
+	dir, err := filepath.EvalSymlinks(pluginsDir)
+	if err != nil {
+		return "", err
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open index dir")
+	}
+	h := sha256.New()
+	for _, f := range files {
+		if f.Mode().IsRegular() && filepath.Ext(f.Name()) == constants.ManifestExtension {
+			fmt.Fprintf(h, "%s %d %d\n", f.Name(), f.Size(), f.ModTime().UnixNano())
+		}
+	}
+	return "files:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func read(path, key string) (List, bool) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.V(1).Infof("WARNING: failed to read plugin list cache: %v", err)
+		}
+		return List{}, false
+	}
+	var c cacheFile
+	if err := json.Unmarshal(b, &c); err != nil {
+		klog.V(1).Infof("WARNING: failed to parse plugin list cache %q: %v", path, err)
+		return List{}, false
+	}
+	if c.Version != version || c.Key != key {
+		klog.V(4).Infof("Plugin list cache %q is outdated", path)
+		return List{}, false
+	}
+	return c.List, true
+}
+
+// write stores c at path. The file is replaced atomically, so that concurrent
+// readers never see a partially written cache.
+func write(path string, c cacheFile) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert plugin list to json")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "failed to create cache directory")
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".cache-*.json")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to write %q", path)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write %q", path)
+	}
+	return errors.Wrapf(os.Rename(f.Name(), path), "failed to write %q", path)
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexcache
+
+import (
+	"os"
+	"testing"
+
+	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+func writePlugin(tmpDir *testutil.TempDir, name string) {
+	tmpDir.WriteYAML("plugins/"+name+constants.ManifestExtension, testutil.NewPlugin().WithName(name).V())
+}
+
+func assertLoad(t *testing.T, tmpDir *testutil.TempDir, revision string, want int) {
+	t.Helper()
+	got, err := Load(tmpDir.Path("cache.json"), tmpDir.Path("plugins"), revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Plugins) != want {
+		t.Errorf("Load(revision=%q) returned %d plugins, want %d", revision, len(got.Plugins), want)
+	}
+}
+
+func TestLoad_revision(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	writePlugin(tmpDir, "foo")
+	assertLoad(t, tmpDir, "a", 1)
+	if _, err := os.Stat(tmpDir.Path("cache.json")); err != nil {
+		t.Fatalf("cache not written: %v", err)
+	}
+
+	writePlugin(tmpDir, "bar")
+	assertLoad(t, tmpDir, "a", 1)
+	assertLoad(t, tmpDir, "b", 2)
+
+	writePlugin(tmpDir, "baz")
+	if err := Invalidate(tmpDir.Path("cache.json")); err != nil {
+		t.Fatal(err)
+	}
+	assertLoad(t, tmpDir, "b", 3)
+}
+
+func TestLoad_withoutRevision(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	writePlugin(tmpDir, "foo")
+	assertLoad(t, tmpDir, "", 1)
+	writePlugin(tmpDir, "bar")
+	assertLoad(t, tmpDir, "", 2)
+}
+
+func TestLoad_corruptCache(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	writePlugin(tmpDir, "foo")
+	tmpDir.Write("cache.json", []byte("{"))
+	assertLoad(t, tmpDir, "a", 1)
+	assertLoad(t, tmpDir, "a", 1)
+}
+
+func TestLoad_failedManifests(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	writePlugin(tmpDir, "foo")
+	tmpDir.Write("plugins/bad"+constants.ManifestExtension, []byte("apiVersion: ["))
+	for i := 0; i < 2; i++ {
+		got, err := Load(tmpDir.Path("cache.json"), tmpDir.Path("plugins"), "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Plugins) != 1 || len(got.Failed) != 1 || got.Failed[0] != "bad" {
+			t.Errorf("Load() = %d plugins, failed %q; want 1 plugin, failed [bad]", len(got.Plugins), got.Failed)
+		}
+	}
+}
+
+func TestInvalidate_missing(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	if err := Invalidate(tmpDir.Path("missing.json")); err != nil {
+		t.Errorf("Invalidate() on missing file: %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package indexconfig reads and writes the configuration file that lists the
+// plugin indexes of a krew installation and their settings.
+package indexconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+const (
+	// APIVersion is the version of the configuration file format written by
+	// this version of krew.
+	APIVersion = "config.krew.sigs.k8s.io/v1alpha1"
+	// Kind is the kind of the configuration file.
+	Kind = "IndexConfig"
+
+	// DefaultIndexPriority is the priority of the default index if none is
+	// configured, so that unqualified plugin names resolve to it unless
+	// another index is given a higher priority.
+	DefaultIndexPriority = 100
+)
+
+// Config lists the configured plugin indexes.
+type Config struct {
+	APIVersion string  `json:"apiVersion"`
+	Kind       string  `json:"kind"`
+	Indexes    []Entry `json:"indexes"`
+}
+
+// Entry holds the settings of a single index.
+type Entry struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Ref is the branch, tag or commit the index is pinned to.
+	Ref string `json:"ref,omitempty"`
+	// Type is the type of the index backend, see package indexbackend.
+	Type string `json:"type,omitempty"`
+	// Priority determines the order in which indexes are searched for
+	// plugins given without an index name, highest first. If it is nil,
+	// DefaultPriority is used.
+	Priority *int `json:"priority,omitempty"`
+	// Trust configures the verification of the index. Indexes without it are
+	// not verified.
+	Trust *Trust `json:"trust,omitempty"`
+}
+
+// Trust holds the keys that an index must be signed with.
+type Trust struct {
+	// Method is how the index is verified, see package indextrust. If it is
+	// empty, git indexes verify the signature of their HEAD commit and other
+	// indexes verify a signed snapshot manifest.
+	Method string `json:"method,omitempty"`
+	// SigningKeys are the paths of ASCII-armored OpenPGP public keys. Relative
+	// paths are relative to the krew root directory.
+	SigningKeys []string `json:"signingKeys"`
+}
+
+// DefaultPriority returns the priority of an index that does not have one
+// configured.
+func DefaultPriority(name string) int {
+	if name == constants.DefaultIndexName {
+		return DefaultIndexPriority
+	}
+	return 0
+}
+
+// EffectivePriority returns the configured priority of the index, or its
+// default priority.
+func (e Entry) EffectivePriority() int {
+	if e.Priority != nil {
+		return *e.Priority
+	}
+	return DefaultPriority(e.Name)
+}
+
+// New returns an empty configuration of the current version.
+func New() Config {
+	return Config{APIVersion: APIVersion, Kind: Kind, Indexes: []Entry{}}
+}
+
+// Find returns the index with the given name.
+func (c Config) Find(name string) (Entry, bool) {
+	for _, e := range c.Indexes {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Index returns the position of the index with the given name, or -1 if it
+// does not exist.
+func (c Config) Index(name string) int {
+	for i, e := range c.Indexes {
+		if e.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Remove removes the index with the given name and tells whether it existed.
+func (c *Config) Remove(name string) bool {
+	for i, e := range c.Indexes {
+		if e.Name == name {
+			c.Indexes = append(c.Indexes[:i], c.Indexes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads the configuration file at path. If the file does not exist, it
+// returns an error that can be tested with os.IsNotExist.
+func Load(path string) (Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var c Config
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return Config{}, errors.Wrapf(err, "failed to parse index configuration %q", path)
+	}
+	if c.APIVersion != APIVersion || c.Kind != Kind {
+		return Config{}, errors.Errorf("unsupported index configuration %q: apiVersion=%q kind=%q", path, c.APIVersion, c.Kind)
+	}
+	if c.Indexes == nil {
+		c.Indexes = []Entry{}
+	}
+	return c, nil
+}
+
+// Save writes the configuration to path. The file is replaced atomically so
+// that it is never left partially written.
+func Save(path string, c Config) error {
+	c.APIVersion, c.Kind = APIVersion, Kind
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert index configuration to yaml")
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".indexes-*.yaml")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to write index configuration %q", path)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write index configuration %q", path)
+	}
+	return errors.Wrapf(os.Rename(f.Name(), path), "failed to write index configuration %q", path)
+}
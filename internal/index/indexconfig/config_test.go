@@ -0,0 +1,66 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexconfig
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/krew/internal/testutil"
+)
+
+func TestLoadSave(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	path := tmpDir.Path("indexes.yaml")
+
+	if _, err := Load(path); !os.IsNotExist(err) {
+		t.Fatalf("expected ENOENT error for missing file, got: %v", err)
+	}
+
+	want := New()
+	want.Indexes = append(want.Indexes,
+		Entry{Name: "default", URL: "https://github.com/kubernetes-sigs/krew-index.git", Type: "git"},
+		Entry{Name: "foo", URL: "https://github.com/foo/index.git", Ref: "v1", Type: "git"})
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("loaded configuration does not match: %s", diff)
+	}
+
+	if !got.Remove("default") || got.Remove("default") {
+		t.Error("expected Remove() to remove the index once")
+	}
+	if _, ok := got.Find("default"); ok {
+		t.Error("expected removed index not to be found")
+	}
+	if e, ok := got.Find("foo"); !ok || e.Ref != "v1" {
+		t.Errorf("Find(\"foo\") = %v, %v", e, ok)
+	}
+}
+
+func TestLoad_unsupportedVersion(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("indexes.yaml", []byte("apiVersion: config.krew.sigs.k8s.io/v99\nkind: IndexConfig\n"))
+	if _, err := Load(tmpDir.Path("indexes.yaml")); err == nil {
+		t.Error("expected error for unsupported apiVersion")
+	}
+}
@@ -15,14 +15,27 @@
 package indexoperations
 
 import (
-	"io/ioutil"
+	"context"
 	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/krew/internal/environment"
-	"sigs.k8s.io/krew/internal/gitutil"
+	"sigs.k8s.io/krew/internal/index/indexbackend"
+	"sigs.k8s.io/krew/internal/index/indexcache"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
+	"sigs.k8s.io/krew/internal/index/indextrust"
+	"sigs.k8s.io/krew/internal/installation"
+	"sigs.k8s.io/krew/internal/installation/receipt"
+	"sigs.k8s.io/krew/internal/pathutil"
+	"sigs.k8s.io/krew/internal/policy"
+	"sigs.k8s.io/krew/pkg/constants"
+	"sigs.k8s.io/krew/pkg/index"
 )
 
 var validNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
@@ -31,54 +44,355 @@ var validNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
 type Index struct {
 	Name string
 	URL  string
+	// Ref is the branch, tag or commit the index is pinned to. It is empty if
+	// the index tracks the default branch of its remote.
+	Ref string
+	// Type is the type of the index backend, see package indexbackend.
+	Type string
+	// Priority determines the order in which indexes are searched for
+	// plugins given without an index name, highest first.
+	Priority int
 }
 
-// ListIndexes returns a slice of Index objects. The path argument is used as
-// the base path of the index.
+// ListIndexes returns the indexes in the index configuration, in the order
+// they were added.
 func ListIndexes(paths environment.Paths) ([]Index, error) {
-	entries, err := ioutil.ReadDir(paths.IndexBase())
+	cfg, err := loadConfig(paths)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to list directory")
+		return nil, err
 	}
-
-	indexes := []Index{}
-	for _, e := range entries {
-		if !e.IsDir() {
-			continue
-		}
-		indexName := e.Name()
-		remote, err := gitutil.GetRemoteURL(paths.IndexPath(indexName))
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to list the remote URL for index %s", indexName)
-		}
-
+	indexes := make([]Index, 0, len(cfg.Indexes))
+	for _, e := range cfg.Indexes {
 		indexes = append(indexes, Index{
-			Name: indexName,
-			URL:  remote,
+			Name:     e.Name,
+			URL:      e.URL,
+			Ref:      e.Ref,
+			Type:     e.Type,
+			Priority: e.EffectivePriority(),
 		})
 	}
 	return indexes, nil
 }
 
-// AddIndex initializes a new index to install plugins from.
-func AddIndex(paths environment.Paths, name, url string) error {
+// AddIndex initializes a new index to install plugins from and adds it to the
+// index configuration with the given priority. The type of the index is
+// selected based on the url. If ref is not empty, the index is pinned to that
+// branch, tag or commit. If trust is not nil, the index must pass verification
+// to be added.
+func AddIndex(paths environment.Paths, name, url, ref string, priority int, trust *indexconfig.Trust) error {
+	cfg, err := loadConfig(paths)
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Find(name); ok {
+		return errors.New("index already exists")
+	}
 	dir := paths.IndexPath(name)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return gitutil.EnsureCloned(url, dir)
-	} else if err != nil {
+	if _, err := os.Stat(dir); err == nil {
+		return errors.New("index already exists")
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if trust != nil && trust.Method != "" && !indextrust.IsValidMethod(trust.Method) {
+		return errors.Errorf("invalid verification method %q", trust.Method)
+	}
+	pol, err := policy.Load()
+	if err != nil {
+		return errors.Wrap(err, "failed to load policy")
+	}
+	if err := pol.CheckIndex(url, trust != nil); err != nil {
+		return err
+	}
+
+	backend := indexbackend.ForURL(url)
+	if err := backend.Add(url, dir, ref); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	entry := indexconfig.Entry{
+		Name:     name,
+		URL:      url,
+		Ref:      ref,
+		Type:     backend.Type(),
+		Priority: &priority,
+		Trust:    trust,
+	}
+	if err := verify(paths, entry); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	cfg.Indexes = append(cfg.Indexes, entry)
+	if err := indexconfig.Save(paths.IndexConfigPath(), cfg); err != nil {
+		os.RemoveAll(dir)
 		return err
 	}
-	return errors.New("index already exists")
+	recordUpdate(paths, entry)
+	return nil
 }
 
-// DeleteIndex removes specified index name. If index does not exist, returns an error that can be tested by os.IsNotExist.
+// UpdateIndex synchronizes the local copy of the index with its source. The
+// update is aborted when ctx is done.
+func UpdateIndex(ctx context.Context, paths environment.Paths, name string) error {
+	cfg, err := loadConfig(paths)
+	if err != nil {
+		return err
+	}
+	e, ok := cfg.Find(name)
+	if ok {
+		pol, err := policy.Load()
+		if err != nil {
+			return errors.Wrap(err, "failed to load policy")
+		}
+		if err := pol.CheckIndex(e.URL, e.Trust != nil); err != nil {
+			return err
+		}
+	}
+	dir := paths.IndexPath(name)
+	backend, err := indexbackend.ForDir(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to determine the type of index %s", name)
+	}
+	if err := backend.Update(ctx, dir); err != nil {
+		return err
+	}
+	if err := indexcache.Invalidate(paths.IndexCachePath(name)); err != nil {
+		return err
+	}
+	if !ok {
+		e = indexconfig.Entry{Name: name}
+	}
+	if err := verify(paths, e); err != nil {
+		return err
+	}
+	recordUpdate(paths, e)
+	return nil
+}
+
+// IndexRevision returns the version of the local copy of the index, such as
+// the commit of a git index. It is empty for indexes that are not versioned.
+func IndexRevision(paths environment.Paths, name string) (string, error) {
+	dir := paths.IndexPath(name)
+	backend, err := indexbackend.ForDir(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to determine the type of index %s", name)
+	}
+	return backend.Revision(dir)
+}
+
+// LoadPluginList returns the plugins in an index. The parsed plugin list is
+// cached until the index changes, invalid manifests are logged and skipped.
+// It is an error if the index is not at the revision that passed verification
+// and fails verification.
+func LoadPluginList(paths environment.Paths, name string) ([]index.Plugin, error) {
+	if err := checkVerified(paths, name); err != nil {
+		return nil, err
+	}
+	revision, err := IndexRevision(paths, name)
+	if err != nil {
+		return nil, err
+	}
+	l, err := indexcache.Load(paths.IndexCachePath(name), paths.IndexPluginsPath(name), revision)
+	return l.Plugins, err
+}
+
+// DeleteIndex removes specified index name from the index configuration and
+// deletes its local copy. If index does not exist, returns an error that can be
+// tested by os.IsNotExist.
 func DeleteIndex(paths environment.Paths, name string) error {
+	cfg, err := loadConfig(paths)
+	if err != nil {
+		return err
+	}
+	if !cfg.Remove(name) {
+		return &os.PathError{Op: "delete index", Path: name, Err: os.ErrNotExist}
+	}
+	if err := os.RemoveAll(paths.IndexPath(name)); err != nil {
+		return err
+	}
+	if err := indexcache.Invalidate(paths.IndexCachePath(name)); err != nil {
+		return err
+	}
+	if err := removeState(paths, name); err != nil {
+		return err
+	}
+	return indexconfig.Save(paths.IndexConfigPath(), cfg)
+}
+
+// RenameIndex renames an index and updates the receipts of the plugins
+// installed from it. If the index does not exist, returns an error that can
+// be tested by os.IsNotExist.
+func RenameIndex(paths environment.Paths, oldName, newName string) error {
+	if !IsValidIndexName(newName) {
+		return errors.Errorf("invalid index name %q", newName)
+	}
+	if oldName == constants.DefaultIndexName {
+		return errors.Errorf("the %q index cannot be renamed, as plugins without an index name are installed from it", constants.DefaultIndexName)
+	}
+	cfg, err := loadConfig(paths)
+	if err != nil {
+		return err
+	}
+	i := cfg.Index(oldName)
+	if i < 0 {
+		return &os.PathError{Op: "rename index", Path: oldName, Err: os.ErrNotExist}
+	}
+	if _, ok := cfg.Find(newName); ok {
+		return errors.Errorf("index %q already exists", newName)
+	}
+	if _, err := os.Stat(paths.IndexPath(newName)); err == nil {
+		return errors.Errorf("index %q already exists", newName)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Rename(paths.IndexPath(oldName), paths.IndexPath(newName)); err != nil {
+		return errors.Wrap(err, "failed to rename the local copy of the index")
+	}
+	cfg.Indexes[i].Name = newName
+	if err := indexconfig.Save(paths.IndexConfigPath(), cfg); err != nil {
+		if renameErr := os.Rename(paths.IndexPath(newName), paths.IndexPath(oldName)); renameErr != nil {
+			klog.Warningf("failed to restore the local copy of index %q: %v", oldName, renameErr)
+		}
+		return err
+	}
+	if err := indexcache.Invalidate(paths.IndexCachePath(oldName)); err != nil {
+		klog.Warningf("%v", err)
+	}
+	if err := os.Rename(paths.IndexStatePath(oldName), paths.IndexStatePath(newName)); err != nil && !os.IsNotExist(err) {
+		klog.Warningf("failed to move the state of index %q: %v", oldName, err)
+	}
+
+	receipts, err := installation.GetInstalledPluginReceipts(paths.InstallReceiptsPath())
+	if err != nil {
+		return errors.Wrap(err, "failed to load installed plugins")
+	}
+	for _, r := range receipts {
+		if r.Status.Source.Name != oldName {
+			continue
+		}
+		klog.V(2).Infof("Updating the index of plugin %q in its receipt", r.Name)
+		r.Status.Source.Name = newName
+		if err := receipt.Store(r, paths.PluginInstallReceiptPath(r.Name)); err != nil {
+			return errors.Wrapf(err, "failed to update the receipt of plugin %q", r.Name)
+		}
+	}
+	return nil
+}
+
+// SetIndexURL changes the source of an index and synchronizes the local copy
+// with it. The new source must be of the same type as the old one. If the
+// index does not exist, returns an error that can be tested by os.IsNotExist.
+func SetIndexURL(ctx context.Context, paths environment.Paths, name, url string) error {
+	cfg, err := loadConfig(paths)
+	if err != nil {
+		return err
+	}
+	i := cfg.Index(name)
+	if i < 0 {
+		return &os.PathError{Op: "set index url", Path: name, Err: os.ErrNotExist}
+	}
+	pol, err := policy.Load()
+	if err != nil {
+		return errors.Wrap(err, "failed to load policy")
+	}
+	if err := pol.CheckIndex(url, cfg.Indexes[i].Trust != nil); err != nil {
+		return err
+	}
+
 	dir := paths.IndexPath(name)
-	if _, err := os.Stat(dir); err != nil {
+	backend, err := indexbackend.ForDir(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to determine the type of index %s", name)
+	}
+	if t := indexbackend.ForURL(url).Type(); t != backend.Type() {
+		return errors.Errorf("cannot change index %q of type %q to a URL of type %q, delete and add the index instead", name, backend.Type(), t)
+	}
+	if err := backend.SetURL(ctx, dir, url); err != nil {
+		return err
+	}
+	cfg.Indexes[i].URL = url
+	if err := indexconfig.Save(paths.IndexConfigPath(), cfg); err != nil {
+		return err
+	}
+	if err := indexcache.Invalidate(paths.IndexCachePath(name)); err != nil {
+		return err
+	}
+	if err := verify(paths, cfg.Indexes[i]); err != nil {
 		return err
 	}
+	recordUpdate(paths, cfg.Indexes[i])
+	return nil
+}
 
-	return os.RemoveAll(dir)
+// SetIndexPriority changes the priority of an index. If index does not exist,
+// returns an error that can be tested by os.IsNotExist.
+func SetIndexPriority(paths environment.Paths, name string, priority int) error {
+	cfg, err := loadConfig(paths)
+	if err != nil {
+		return err
+	}
+	for i := range cfg.Indexes {
+		if cfg.Indexes[i].Name == name {
+			cfg.Indexes[i].Priority = &priority
+			return indexconfig.Save(paths.IndexConfigPath(), cfg)
+		}
+	}
+	return &os.PathError{Op: "set index priority", Path: name, Err: os.ErrNotExist}
+}
+
+// ResolvePluginName returns the index and the name of the plugin referred to
+// by in. Names in the INDEX/PLUGIN form are returned as is. Other names are
+// looked up in the configured indexes from the highest to the lowest priority
+// and resolve to the first index that has the plugin. It is an error if more
+// than one index with the same priority has the plugin. If no index has the
+// plugin, the default index is returned.
+func ResolvePluginName(paths environment.Paths, in string) (string, string, error) {
+	if strings.Contains(in, "/") {
+		indexName, pluginName := pathutil.CanonicalPluginName(in)
+		return indexName, pluginName, nil
+	}
+	indexes, err := ListIndexes(paths)
+	if err != nil {
+		return "", "", err
+	}
+	sort.SliceStable(indexes, func(i, j int) bool { return indexes[i].Priority > indexes[j].Priority })
+
+	for i := 0; i < len(indexes); {
+		var candidates []string
+		j := i
+		for ; j < len(indexes) && indexes[j].Priority == indexes[i].Priority; j++ {
+			manifest := filepath.Join(paths.IndexPluginsPath(indexes[j].Name), in+constants.ManifestExtension)
+			if _, err := os.Stat(manifest); err == nil {
+				candidates = append(candidates, indexes[j].Name)
+			} else if !os.IsNotExist(err) {
+				return "", "", errors.Wrapf(err, "failed to look up plugin %q in index %q", in, indexes[j].Name)
+			}
+		}
+		switch len(candidates) {
+		case 0:
+			i = j
+		case 1:
+			klog.V(2).Infof("Resolved plugin %q to index %q", in, candidates[0])
+			return candidates[0], in, nil
+		default:
+			for k := range candidates {
+				candidates[k] += "/" + in
+			}
+			return "", "", errors.Errorf("plugin %q is available from multiple indexes with the same priority, specify one of: %s",
+				in, strings.Join(candidates, ", "))
+		}
+	}
+	return constants.DefaultIndexName, in, nil
+}
+
+// loadConfig reads the index configuration. A missing configuration file means
+// that no indexes are configured.
+func loadConfig(paths environment.Paths) (indexconfig.Config, error) {
+	cfg, err := indexconfig.Load(paths.IndexConfigPath())
+	if os.IsNotExist(err) {
+		return indexconfig.New(), nil
+	}
+	return cfg, errors.Wrap(err, "failed to read index configuration")
 }
 
 // IsValidIndexName validates if an index name contains invalid characters
@@ -15,14 +15,22 @@
 package indexoperations
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
 	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/gitutil"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
+	"sigs.k8s.io/krew/internal/installation/receipt"
+	"sigs.k8s.io/krew/internal/policy"
 	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/constants"
+	"sigs.k8s.io/krew/pkg/index"
 )
 
 func TestListIndexes(t *testing.T) {
@@ -32,18 +40,26 @@ func TestListIndexes(t *testing.T) {
 		{
 			Name: "custom",
 			URL:  "https://github.com/custom/index.git",
+			Type: "git",
 		},
 		{
-			Name: "default",
-			URL:  "https://github.com/default/index.git",
+			Name:     "default",
+			URL:      "https://github.com/default/index.git",
+			Type:     "git",
+			Priority: indexconfig.DefaultIndexPriority,
 		},
 	}
 
 	paths := environment.NewPaths(tmpDir.Root())
+	cfg := indexconfig.New()
 	for _, index := range wantIndexes {
-		path := paths.IndexPath(index.Name)
-		tmpDir.InitEmptyGitRepo(path, index.URL)
+		cfg.Indexes = append(cfg.Indexes, indexconfig.Entry{Name: index.Name, URL: index.URL, Type: index.Type})
 	}
+	if err := indexconfig.Save(paths.IndexConfigPath(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	// directories that are not in the configuration are not indexes
+	tmpDir.InitEmptyGitRepo(paths.IndexPath("unknown"), "https://github.com/unknown/index.git")
 
 	gotIndexes, err := ListIndexes(paths)
 	if err != nil {
@@ -62,7 +78,7 @@ func TestAddIndexSuccess(t *testing.T) {
 	tmpDir.InitEmptyGitRepo(localRepo, "")
 
 	paths := environment.NewPaths(tmpDir.Root())
-	if err := AddIndex(paths, indexName, localRepo); err != nil {
+	if err := AddIndex(paths, indexName, localRepo, "", 0, nil); err != nil {
 		t.Errorf("error adding index: %v", err)
 	}
 	gotIndexes, err := ListIndexes(paths)
@@ -73,6 +89,7 @@ func TestAddIndexSuccess(t *testing.T) {
 		{
 			Name: indexName,
 			URL:  localRepo,
+			Type: "git",
 		},
 	}
 	if diff := cmp.Diff(wantIndexes, gotIndexes); diff != "" {
@@ -80,12 +97,65 @@ func TestAddIndexSuccess(t *testing.T) {
 	}
 }
 
+func TestAddIndexWithRef(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+
+	localRepo := tmpDir.Path("local/foo")
+	tmpDir.InitEmptyGitRepo(localRepo, "")
+	commit := func(msg string) {
+		t.Helper()
+		if _, err := gitutil.Exec(localRepo, "-c", "user.name=krew", "-c", "user.email=krew@example.com",
+			"commit", "--allow-empty", "-m", msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("first")
+	if _, err := gitutil.Exec(localRepo, "tag", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	commit("second")
+	wantCommit, err := gitutil.Exec(localRepo, "rev-parse", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := environment.NewPaths(tmpDir.Root())
+	if err := AddIndex(paths, "foo", localRepo, "v1", 0, nil); err != nil {
+		t.Fatalf("error adding index: %v", err)
+	}
+	gotIndexes, err := ListIndexes(paths)
+	if err != nil {
+		t.Fatalf("error listing indexes: %s", err)
+	}
+	if diff := cmp.Diff([]Index{{Name: "foo", URL: localRepo, Ref: "v1", Type: "git"}}, gotIndexes); diff != "" {
+		t.Errorf("expected pinned index in list: %s", diff)
+	}
+
+	if err := gitutil.EnsureUpdated(localRepo, paths.IndexPath("foo")); err != nil {
+		t.Fatalf("error updating index: %v", err)
+	}
+	gotCommit, err := gitutil.Exec(paths.IndexPath("foo"), "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotCommit != wantCommit {
+		t.Errorf("expected index to stay at commit %s after update, got %s", wantCommit, gotCommit)
+	}
+
+	if err := AddIndex(paths, "bar", localRepo, "does-not-exist", 0, nil); err == nil {
+		t.Error("expected error when adding index with a nonexistent ref")
+	}
+	if _, err := os.Stat(paths.IndexPath("bar")); !os.IsNotExist(err) {
+		t.Errorf("expected index directory to be removed after a failed add, got: %v", err)
+	}
+}
+
 func TestAddIndexFailure(t *testing.T) {
 	tmpDir := testutil.NewTempDir(t)
 
 	indexName := "foo"
 	paths := environment.NewPaths(tmpDir.Root())
-	if err := AddIndex(paths, indexName, tmpDir.Path("invalid/repo")); err == nil {
+	if err := AddIndex(paths, indexName, tmpDir.Path("invalid/repo"), "", 0, nil); err == nil {
 		t.Error("expected error when adding index with invalid URL")
 	}
 
@@ -93,11 +163,11 @@ func TestAddIndexFailure(t *testing.T) {
 	tmpDir.InitEmptyGitRepo(tmpDir.Path("index/"+indexName), "")
 	tmpDir.InitEmptyGitRepo(localRepo, "")
 
-	if err := AddIndex(paths, indexName, localRepo); err == nil {
+	if err := AddIndex(paths, indexName, localRepo, "", 0, nil); err == nil {
 		t.Error("expected error when adding an index that already exists")
 	}
 
-	if err := AddIndex(paths, "foo/bar", ""); err == nil {
+	if err := AddIndex(paths, "foo/bar", "", "", 0, nil); err == nil {
 		t.Error("expected error with invalid index name")
 	}
 }
@@ -120,13 +190,249 @@ func TestDeleteIndex(t *testing.T) {
 		t.Fatalf("not ENOENT error: %v", err)
 	}
 
-	if err := os.MkdirAll(p.IndexPath("some-index"), 0755); err != nil {
+	localRepo := tmpDir.Path("local/some-index")
+	tmpDir.InitEmptyGitRepo(localRepo, "")
+	if err := AddIndex(p, "some-index", localRepo, "", 0, nil); err != nil {
 		t.Fatalf("err creating test index: %v", err)
 	}
 
 	if err := DeleteIndex(p, "some-index"); err != nil {
 		t.Fatalf("got error while deleting index: %v", err)
 	}
+	if _, err := os.Stat(p.IndexPath("some-index")); !os.IsNotExist(err) {
+		t.Errorf("expected index directory to be deleted, got: %v", err)
+	}
+	if got, err := ListIndexes(p); err != nil || len(got) != 0 {
+		t.Errorf("expected no indexes after delete, got %v, err: %v", got, err)
+	}
+}
+
+func TestSetIndexPriority(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+
+	if err := SetIndexPriority(p, "foo", 10); !os.IsNotExist(err) {
+		t.Fatalf("expected ENOENT error for unknown index, got: %v", err)
+	}
+
+	localRepo := tmpDir.Path("local/foo")
+	tmpDir.InitEmptyGitRepo(localRepo, "")
+	if err := AddIndex(p, "foo", localRepo, "", 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetIndexPriority(p, "foo", 10); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ListIndexes(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Priority != 10 {
+		t.Errorf("expected index with priority 10, got %v", got)
+	}
+}
+
+func TestRenameIndex(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+
+	if err := RenameIndex(p, "foo", "bar"); !os.IsNotExist(err) {
+		t.Fatalf("expected ENOENT error for unknown index, got: %v", err)
+	}
+
+	for _, name := range []string{"foo", "other"} {
+		localRepo := tmpDir.Path("local/" + name)
+		tmpDir.InitEmptyGitRepo(localRepo, "")
+		if err := AddIndex(p, name, localRepo, "", 0, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	receiptFromIndex := func(plugin, indexName string) index.Receipt {
+		return testutil.NewReceipt().WithPlugin(testutil.NewPlugin().WithName(plugin).V()).
+			WithStatus(index.ReceiptStatus{Source: index.SourceIndex{Name: indexName}}).V()
+	}
+	tmpDir.WriteYAML("receipts/a.yaml", receiptFromIndex("a", "foo"))
+	tmpDir.WriteYAML("receipts/b.yaml", receiptFromIndex("b", "other"))
+
+	if err := RenameIndex(p, "foo", "other"); err == nil {
+		t.Error("expected error renaming to an existing index")
+	}
+	if err := RenameIndex(p, constants.DefaultIndexName, "bar"); err == nil || os.IsNotExist(err) {
+		t.Errorf("expected error renaming the default index, got: %v", err)
+	}
+	if err := RenameIndex(p, "foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ListIndexes(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "bar" {
+		t.Errorf("expected renamed index in list, got %v", got)
+	}
+	if _, err := os.Stat(p.IndexPath("bar")); err != nil {
+		t.Errorf("expected local copy of the renamed index: %v", err)
+	}
+	for plugin, want := range map[string]string{"a": "bar", "b": "other"} {
+		r, err := receipt.Load(p.PluginInstallReceiptPath(plugin))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Status.Source.Name != want {
+			t.Errorf("receipt of %q has index %q, want %q", plugin, r.Status.Source.Name, want)
+		}
+	}
+}
+
+func TestSetIndexURL(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+	ctx := context.Background()
+
+	if err := SetIndexURL(ctx, p, "foo", "https://example.com/index.git"); !os.IsNotExist(err) {
+		t.Fatalf("expected ENOENT error for unknown index, got: %v", err)
+	}
+
+	origin, mirror := tmpDir.Path("origin"), tmpDir.Path("mirror")
+	tmpDir.InitEmptyGitRepo(origin, "")
+	commit := func(dir, msg string) {
+		t.Helper()
+		if _, err := gitutil.Exec(dir, "-c", "user.name=krew", "-c", "user.email=krew@example.com",
+			"commit", "--allow-empty", "-m", msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit(origin, "first")
+	if _, err := gitutil.Exec("", "clone", "-q", origin, mirror); err != nil {
+		t.Fatal(err)
+	}
+	commit(mirror, "second")
+	if err := AddIndex(p, "foo", origin, "", 0, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetIndexURL(ctx, p, "foo", tmpDir.Path("missing")); err == nil {
+		t.Error("expected error for a URL that cannot be fetched")
+	}
+	if got, err := gitutil.GetRemoteURL(p.IndexPath("foo")); err != nil || got != origin {
+		t.Errorf("expected remote to stay %q after failure, got %q, %v", origin, got, err)
+	}
+	if err := SetIndexURL(ctx, p, "foo", "https://example.com/index.tar.gz"); err == nil {
+		t.Error("expected error for a URL of another type")
+	}
+
+	if err := SetIndexURL(ctx, p, "foo", mirror); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ListIndexes(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].URL != mirror {
+		t.Errorf("expected index with URL %q, got %v", mirror, got)
+	}
+	want, err := gitutil.GetCommit(mirror)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit, err := IndexRevision(p, "foo"); err != nil || commit != want {
+		t.Errorf("IndexRevision() = %q, %v; want %q", commit, err, want)
+	}
+}
+
+func TestIndexURLPolicy(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+	ctx := context.Background()
+
+	origin, mirror := tmpDir.Path("origin"), tmpDir.Path("mirror")
+	tmpDir.InitEmptyGitRepo(origin, "")
+	if _, err := gitutil.Exec(origin, "-c", "user.name=krew", "-c", "user.email=krew@example.com",
+		"commit", "--allow-empty", "-m", "first"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gitutil.Exec("", "clone", "-q", origin, mirror); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddIndex(p, "foo", origin, "", 0, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir.Write("policy.yaml", []byte("apiVersion: policy.krew.sigs.k8s.io/v1alpha1\nkind: Policy\n"+
+		"allowedIndexURLs:\n- "+mirror+"\n"))
+	os.Setenv(policy.Env, tmpDir.Path("policy.yaml"))
+	defer os.Unsetenv(policy.Env)
+
+	if err := UpdateIndex(ctx, p, "foo"); !policy.IsViolation(err) {
+		t.Errorf("expected policy violation updating an index with a URL that is not allowed, got: %v", err)
+	}
+	if err := SetIndexURL(ctx, p, "foo", tmpDir.Path("other")); !policy.IsViolation(err) {
+		t.Errorf("expected policy violation setting a URL that is not allowed, got: %v", err)
+	}
+	if err := SetIndexURL(ctx, p, "foo", mirror); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateIndex(ctx, p, "foo"); err != nil {
+		t.Errorf("expected update of an allowed index to succeed, got: %v", err)
+	}
+}
+
+func TestResolvePluginName(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+
+	priority := func(n int) *int { return &n }
+	cfg := indexconfig.New()
+	cfg.Indexes = []indexconfig.Entry{
+		{Name: "default"},
+		{Name: "corp"},
+		{Name: "team-a", Priority: priority(50)},
+		{Name: "team-b", Priority: priority(50)},
+	}
+	if err := indexconfig.Save(p.IndexConfigPath(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	tmpDir.Write("index/default/plugins/foo.yaml", nil)
+	tmpDir.Write("index/corp/plugins/foo.yaml", nil)
+	tmpDir.Write("index/corp/plugins/bar.yaml", nil)
+	tmpDir.Write("index/team-a/plugins/baz.yaml", nil)
+	tmpDir.Write("index/team-b/plugins/baz.yaml", nil)
+	tmpDir.Write("index/team-b/plugins/bar.yaml", nil)
+
+	tests := []struct {
+		in         string
+		wantIndex  string
+		wantPlugin string
+		wantErr    bool
+	}{
+		{in: "foo", wantIndex: "default", wantPlugin: "foo"},
+		{in: "corp/foo", wantIndex: "corp", wantPlugin: "foo"},
+		{in: "bar", wantIndex: "team-b", wantPlugin: "bar"},
+		{in: "baz", wantErr: true},
+		{in: "team-a/baz", wantIndex: "team-a", wantPlugin: "baz"},
+		{in: "missing", wantIndex: "default", wantPlugin: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			gotIndex, gotPlugin, err := ResolvePluginName(p, tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error for ambiguous name")
+				}
+				if !strings.Contains(err.Error(), "team-a/baz, team-b/baz") {
+					t.Errorf("expected error to list candidates, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotIndex != tt.wantIndex || gotPlugin != tt.wantPlugin {
+				t.Errorf("ResolvePluginName(%q) = %q, %q; want %q, %q", tt.in, gotIndex, gotPlugin, tt.wantIndex, tt.wantPlugin)
+			}
+		})
+	}
 }
 
 func TestIsValidIndexName(t *testing.T) {
@@ -0,0 +1,143 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexoperations
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/index/indexbackend"
+	"sigs.k8s.io/krew/internal/index/indexcache"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
+	"sigs.k8s.io/krew/internal/index/indexscanner"
+)
+
+// Status describes the freshness and health of the local copy of an index.
+type Status struct {
+	// LastUpdate is the time the index was last added or updated
+	// successfully. It is zero if unknown.
+	LastUpdate time.Time
+	// Revision is the version of the local copy, see IndexRevision.
+	Revision string
+	// Plugins is the number of plugins in the index.
+	Plugins int
+	// FailedManifests is the number of plugin manifests in the index that
+	// could not be read or parsed.
+	FailedManifests int
+	// Dirty is set if files in the local copy were changed.
+	Dirty bool
+	// Diverged is set if the local copy is not at the revision of its ref.
+	Diverged bool
+}
+
+// state is stored in the file at environment.Paths.IndexStatePath.
+type state struct {
+	LastUpdate time.Time `json:"lastUpdate"`
+	// Verified identifies the revision that passed verification when the
+	// index was last added or updated, see verifiedID.
+	Verified string `json:"verified,omitempty"`
+}
+
+// IndexStatus reports the status of the local copy of an index. The index is
+// not verified.
+func IndexStatus(paths environment.Paths, name string) (Status, error) {
+	var s Status
+	dir := paths.IndexPath(name)
+	backend, err := indexbackend.ForDir(dir)
+	if err != nil {
+		return s, errors.Wrapf(err, "failed to determine the type of index %s", name)
+	}
+	if s.Revision, err = backend.Revision(dir); err != nil {
+		return s, err
+	}
+	bs, err := backend.Status(dir)
+	if err != nil {
+		return s, err
+	}
+	s.Dirty, s.Diverged = bs.Dirty, bs.Diverged
+
+	// The cache is keyed by revision, so it does not reflect local changes.
+	var l indexcache.List
+	if s.Dirty {
+		l.Plugins, l.Failed, err = indexscanner.LoadPluginListWithFailures(paths.IndexPluginsPath(name))
+	} else {
+		l, err = indexcache.Load(paths.IndexCachePath(name), paths.IndexPluginsPath(name), s.Revision)
+	}
+	if err != nil {
+		return s, err
+	}
+	s.Plugins, s.FailedManifests = len(l.Plugins), len(l.Failed)
+
+	st, err := readState(paths, name)
+	if err != nil {
+		return s, err
+	}
+	s.LastUpdate = st.LastUpdate
+	return s, nil
+}
+
+func readState(paths environment.Paths, name string) (state, error) {
+	var st state
+	b, err := ioutil.ReadFile(paths.IndexStatePath(name))
+	if os.IsNotExist(err) {
+		return st, nil
+	} else if err != nil {
+		return st, errors.Wrapf(err, "failed to read the state of index %q", name)
+	}
+	return st, errors.Wrapf(json.Unmarshal(b, &st), "failed to parse the state of index %q", name)
+}
+
+// recordUpdate stores the current time as the last update of index e, and
+// the revision of its local copy if it passed verification. Failing to store
+// them is not an error: the time is only informational, and an index without
+// a recorded revision is verified when it is read.
+func recordUpdate(paths environment.Paths, e indexconfig.Entry) {
+	name := e.Name
+	st := state{LastUpdate: time.Now().UTC().Truncate(time.Second)}
+	if e.Trust != nil {
+		revision, err := IndexRevision(paths, name)
+		if err != nil {
+			klog.V(1).Infof("WARNING: failed to get the revision of index %q: %v", name, err)
+		}
+		st.Verified = verifiedID(e, revision)
+	}
+	path := paths.IndexStatePath(name)
+	b, err := json.Marshal(st)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(path, b, 0644)
+	}
+	if err != nil {
+		klog.V(1).Infof("WARNING: failed to record the update of index %q: %v", name, err)
+	}
+}
+
+// removeState deletes the state of an index. It is not an error if the index
+// has no state.
+func removeState(paths environment.Paths, name string) error {
+	if err := os.Remove(paths.IndexStatePath(name)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove the state of index %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexoperations
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/gitutil"
+	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+func TestIndexStatus(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+
+	origin := tmpDir.Path("origin")
+	tmpDir.InitEmptyGitRepo(origin, "")
+	tmpDir.WriteYAML("origin/plugins/foo"+constants.ManifestExtension, testutil.NewPlugin().WithName("foo").V())
+	tmpDir.Write("origin/plugins/bad"+constants.ManifestExtension, []byte("apiVersion: ["))
+	for _, args := range [][]string{
+		{"add", "."},
+		{"-c", "user.name=krew", "-c", "user.email=krew@example.com", "commit", "-m", "first"},
+	} {
+		if _, err := gitutil.Exec(origin, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit, err := gitutil.GetCommit(origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now().Add(-time.Second)
+	if err := AddIndex(p, "foo", origin, "", 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := IndexStatus(p, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastUpdate.Before(before) || got.LastUpdate.After(time.Now()) {
+		t.Errorf("expected the last update to be the time the index was added, got %v", got.LastUpdate)
+	}
+	got.LastUpdate = time.Time{}
+	if want := (Status{Revision: commit, Plugins: 1, FailedManifests: 1}); got != want {
+		t.Errorf("IndexStatus() = %+v, want %+v", got, want)
+	}
+
+	tmpDir.Write("index/foo/plugins/foo"+constants.ManifestExtension, []byte("changed"))
+	if got, err := IndexStatus(p, "foo"); err != nil || !got.Dirty || got.Diverged {
+		t.Errorf("expected a changed manifest to make the index dirty, got %+v, %v", got, err)
+	}
+
+	if err := DeleteIndex(p, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(p.IndexStatePath("foo")); !os.IsNotExist(err) {
+		t.Errorf("expected the state of the deleted index to be removed, got: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexoperations
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/index/indexbackend"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
+	"sigs.k8s.io/krew/internal/index/indexscanner"
+	"sigs.k8s.io/krew/internal/index/indextrust"
+	"sigs.k8s.io/krew/pkg/index"
+)
+
+// keyRings caches the keyrings read by readKeyRing by their key files, so that
+// the keys of an index are read once per command instead of on every lookup.
+var keyRings = struct {
+	sync.Mutex
+	m map[string]openpgp.EntityList
+}{m: make(map[string]openpgp.EntityList)}
+
+// checkVerified checks that the local copy of an index is at the revision
+// that was verified when it was last added or updated. Indexes whose backend
+// does not track revisions, and indexes at another revision, are verified
+// again.
+func checkVerified(paths environment.Paths, name string) error {
+	cfg, err := loadConfig(paths)
+	if err != nil {
+		return err
+	}
+	e, ok := cfg.Find(name)
+	if !ok || e.Trust == nil {
+		return nil
+	}
+	if revision, err := IndexRevision(paths, name); err == nil && revision != "" {
+		if st, err := readState(paths, name); err == nil && st.Verified == verifiedID(e, revision) {
+			return nil
+		}
+	}
+	klog.V(2).Infof("Index %q is not at the verified revision, verifying it", name)
+	return verify(paths, e)
+}
+
+// verifiedID identifies the revision of index e that was verified with its
+// trust configuration, so that changing the configuration requires another
+// verification. It is empty if e has no trust configuration.
+func verifiedID(e indexconfig.Entry, revision string) string {
+	if e.Trust == nil || revision == "" {
+		return ""
+	}
+	return strings.Join(append([]string{revision, e.Trust.Method}, e.Trust.SigningKeys...), "\x00")
+}
+
+// LoadPlugin loads a plugin from an index, unless the index fails
+// verification. If the plugin does not exist, it returns an error that can be
+// checked with os.IsNotExist.
+func LoadPlugin(paths environment.Paths, indexName, pluginName string) (index.Plugin, error) {
+	if err := checkVerified(paths, indexName); err != nil {
+		return index.Plugin{}, err
+	}
+	return indexscanner.LoadPluginByName(paths.IndexPluginsPath(indexName), pluginName)
+}
+
+// verify checks that the local copy of index e is signed by one of the keys in
+// its trust configuration. Indexes without a trust configuration are not
+// verified.
+func verify(paths environment.Paths, e indexconfig.Entry) error {
+	if e.Trust == nil {
+		return nil
+	}
+	keyFiles := make([]string, 0, len(e.Trust.SigningKeys))
+	for _, k := range e.Trust.SigningKeys {
+		if !filepath.IsAbs(k) {
+			k = filepath.Join(paths.BasePath(), k)
+		}
+		keyFiles = append(keyFiles, k)
+	}
+	keys, err := readKeyRing(keyFiles)
+	if err != nil {
+		return errors.Wrapf(err, "failed to verify index %q", e.Name)
+	}
+
+	dir := paths.IndexPath(e.Name)
+	method := e.Trust.Method
+	if method == "" {
+		method = indextrust.MethodSnapshot
+		if b, err := indexbackend.ForDir(dir); err != nil {
+			return errors.Wrapf(err, "failed to determine the type of index %s", e.Name)
+		} else if b.Type() == indexbackend.TypeGit {
+			method = indextrust.MethodCommit
+		}
+	}
+	switch method {
+	case indextrust.MethodCommit:
+		err = indextrust.VerifyCommit(dir, keys)
+	case indextrust.MethodSnapshot:
+		err = indextrust.VerifySnapshot(paths.IndexPluginsPath(e.Name), keys)
+	default:
+		err = errors.Errorf("invalid verification method %q", method)
+	}
+	return errors.Wrapf(err, "index %q failed verification", e.Name)
+}
+
+// readKeyRing reads the keys in files, or returns them from keyRings if they
+// were already read.
+func readKeyRing(files []string) (openpgp.EntityList, error) {
+	id := strings.Join(files, string(filepath.ListSeparator))
+	keyRings.Lock()
+	defer keyRings.Unlock()
+	if keys, ok := keyRings.m[id]; ok {
+		return keys, nil
+	}
+	keys, err := indextrust.ReadKeyRing(files)
+	if err != nil {
+		return nil, err
+	}
+	keyRings.m[id] = keys
+	return keys, nil
+}
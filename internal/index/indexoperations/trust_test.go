@@ -0,0 +1,177 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexoperations
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/gitutil"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
+	"sigs.k8s.io/krew/internal/index/indextrust"
+	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+// signedIndex writes a local index with a plugin named foo and a snapshot
+// manifest signed with a new key, and returns the path of the public key.
+func signedIndex(t *testing.T, tmpDir *testutil.TempDir) string {
+	t.Helper()
+	key, err := openpgp.NewEntity("krew", "", "krew@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pub bytes.Buffer
+	w, err := armor.Encode(&pub, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := key.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tmpDir.Write("key.asc", pub.Bytes())
+
+	manifest, err := yaml.Marshal(testutil.NewPlugin().WithName("foo").V())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(manifest)
+	sums := []byte(fmt.Sprintf("%s  foo%s\n", hex.EncodeToString(sum[:]), constants.ManifestExtension))
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, key, bytes.NewReader(sums), nil); err != nil {
+		t.Fatal(err)
+	}
+	tmpDir.Write("source/plugins/foo"+constants.ManifestExtension, manifest)
+	tmpDir.Write("source/plugins/"+indextrust.SnapshotManifest, sums)
+	tmpDir.Write("source/plugins/"+indextrust.SnapshotSignature, sig.Bytes())
+	return tmpDir.Path("key.asc")
+}
+
+func TestAddIndex_trusted(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	key := signedIndex(t, tmpDir)
+	paths := environment.NewPaths(tmpDir.Path("krew"))
+	url := "file://" + filepath.ToSlash(tmpDir.Path("source"))
+
+	if err := AddIndex(paths, "foo", url, "", 0, &indexconfig.Trust{SigningKeys: []string{key}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPlugin(paths, "foo", "foo"); err != nil {
+		t.Errorf("LoadPlugin() from verified index: %v", err)
+	}
+
+	tmpDir.WriteYAML("source/plugins/foo"+constants.ManifestExtension, testutil.NewPlugin().WithName("foo").WithVersion("v9.9.9").V())
+	if _, err := LoadPlugin(paths, "foo", "foo"); err == nil {
+		t.Error("expected LoadPlugin() to fail for a modified index")
+	}
+	if _, err := LoadPluginList(paths, "foo"); err == nil {
+		t.Error("expected LoadPluginList() to fail for a modified index")
+	}
+}
+
+func TestLoadPlugin_verifiedRevision(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	key := signedIndex(t, tmpDir)
+	source := tmpDir.Path("source")
+	tmpDir.InitEmptyGitRepo(source, "")
+	if _, err := gitutil.Exec(source, "add", "."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gitutil.Exec(source, "-c", "user.name=krew", "-c", "user.email=krew@example.com", "commit", "-m", "index"); err != nil {
+		t.Fatal(err)
+	}
+	paths := environment.NewPaths(tmpDir.Path("krew"))
+	trust := &indexconfig.Trust{Method: indextrust.MethodSnapshot, SigningKeys: []string{key}}
+	if err := AddIndex(paths, "foo", "file://"+filepath.ToSlash(source), "", 0, trust); err != nil {
+		t.Fatal(err)
+	}
+
+	// reads at the verified revision do not need the keys
+	if err := os.Remove(key); err != nil {
+		t.Fatal(err)
+	}
+	keyRings.Lock()
+	keyRings.m = make(map[string]openpgp.EntityList)
+	keyRings.Unlock()
+	if _, err := LoadPlugin(paths, "foo", "foo"); err != nil {
+		t.Errorf("LoadPlugin() at the verified revision: %v", err)
+	}
+	if _, err := LoadPluginList(paths, "foo"); err != nil {
+		t.Errorf("LoadPluginList() at the verified revision: %v", err)
+	}
+
+	tmpDir.WriteYAML("krew/state/index/foo.json", state{Verified: "other revision"})
+	if _, err := LoadPlugin(paths, "foo", "foo"); err == nil {
+		t.Error("expected LoadPlugin() to verify an index at another revision")
+	}
+}
+
+func Test_readKeyRing_cached(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	key := signedIndex(t, tmpDir)
+
+	first, err := readKeyRing([]string{key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(key); err != nil {
+		t.Fatal(err)
+	}
+	second, err := readKeyRing([]string{key})
+	if err != nil {
+		t.Fatalf("expected the keyring to be read only once, got: %v", err)
+	}
+	if len(first) != 1 || len(second) != 1 || first[0] != second[0] {
+		t.Errorf("expected the cached keyring, got %v and %v", first, second)
+	}
+	if _, err := readKeyRing([]string{tmpDir.Path("missing.asc")}); err == nil {
+		t.Error("expected error for a missing key file")
+	}
+}
+
+func TestAddIndex_failsVerification(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	signedIndex(t, tmpDir)
+	other := testutil.NewTempDir(t)
+	otherKey := signedIndex(t, other)
+	paths := environment.NewPaths(tmpDir.Path("krew"))
+	url := "file://" + filepath.ToSlash(tmpDir.Path("source"))
+
+	if err := AddIndex(paths, "foo", url, "", 0, &indexconfig.Trust{SigningKeys: []string{otherKey}}); err == nil {
+		t.Fatal("expected error adding an index signed with another key")
+	}
+	if _, err := os.Stat(paths.IndexPath("foo")); !os.IsNotExist(err) {
+		t.Errorf("expected index that failed verification to be removed, got: %v", err)
+	}
+	if indexes, err := ListIndexes(paths); err != nil || len(indexes) != 0 {
+		t.Errorf("expected no indexes, got %v, %v", indexes, err)
+	}
+	if err := AddIndex(paths, "foo", url, "", 0, &indexconfig.Trust{Method: "foo", SigningKeys: []string{otherKey}}); err == nil {
+		t.Error("expected error for invalid verification method")
+	}
+}
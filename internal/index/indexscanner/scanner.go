@@ -23,11 +23,11 @@ import (
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
-	"sigs.k8s.io/yaml"
 
 	"sigs.k8s.io/krew/internal/index/validation"
 	"sigs.k8s.io/krew/pkg/constants"
 	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/scheme"
 )
 
 func findPluginManifestFiles(indexDir string) ([]string, error) {
@@ -46,18 +46,26 @@ func findPluginManifestFiles(indexDir string) ([]string, error) {
 
 // LoadPluginListFromFS will parse and retrieve all plugin files.
 func LoadPluginListFromFS(indexDir string) ([]index.Plugin, error) {
+	list, _, err := LoadPluginListWithFailures(indexDir)
+	return list, err
+}
+
+// LoadPluginListWithFailures is like LoadPluginListFromFS, but also returns
+// the names of the plugins whose manifests could not be read or parsed.
+func LoadPluginListWithFailures(indexDir string) ([]index.Plugin, []string, error) {
 	indexDir, err := filepath.EvalSymlinks(indexDir)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	files, err := findPluginManifestFiles(indexDir)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to scan plugins in index directory")
+		return nil, nil, errors.Wrap(err, "failed to scan plugins in index directory")
 	}
 	klog.V(4).Infof("found %d plugins in dir %s", len(files), indexDir)
 
 	list := make([]index.Plugin, 0, len(files))
+	var failed []string
 	for _, file := range files {
 		pluginName := strings.TrimSuffix(file, filepath.Ext(file))
 		p, err := LoadPluginByName(indexDir, pluginName)
@@ -65,11 +73,12 @@ func LoadPluginListFromFS(indexDir string) ([]index.Plugin, error) {
 			// Index loading shouldn't fail because of one plugin.
 			// Show error instead.
 			klog.Errorf("failed to read or parse plugin manifest %q: %v", pluginName, err)
+			failed = append(failed, pluginName)
 			continue
 		}
 		list = append(list, p)
 	}
-	return list, nil
+	return list, failed, nil
 }
 
 // LoadPluginByName loads a plugins index file by its name. When plugin
@@ -82,17 +91,24 @@ func LoadPluginByName(pluginsDir, pluginName string) (index.Plugin, error) {
 // ReadPluginFromFile loads a file from the FS. When plugin file not found, it
 // returns an error that can be checked with os.IsNotExist.
 func ReadPluginFromFile(path string) (index.Plugin, error) {
-	var plugin index.Plugin
-	err := readFromFile(path, &plugin)
+	b, err := readFile(path)
+	if err != nil {
+		return index.Plugin{}, err
+	}
+	plugin, err := scheme.DecodePlugin(b)
 	if err != nil {
-		return plugin, err
+		return plugin, errors.Wrapf(err, "failed to parse yaml file %q", path)
 	}
 	return plugin, errors.Wrap(validation.ValidatePlugin(plugin.Name, plugin), "plugin manifest validation error")
 }
 
 func ReadPlugin(f io.ReadCloser) (index.Plugin, error) {
-	var plugin index.Plugin
-	err := decodeFile(f, &plugin)
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return index.Plugin{}, errors.Wrap(err, "failed to decode plugin manifest")
+	}
+	plugin, err := scheme.DecodePlugin(b)
 	if err != nil {
 		return plugin, errors.Wrap(err, "failed to decode plugin manifest")
 	}
@@ -102,36 +118,25 @@ func ReadPlugin(f io.ReadCloser) (index.Plugin, error) {
 // ReadReceiptFromFile loads a file from the FS. When receipt file not found, it
 // returns an error that can be checked with os.IsNotExist.
 func ReadReceiptFromFile(path string) (index.Receipt, error) {
-	var receipt index.Receipt
-	err := readFromFile(path, &receipt)
+	b, err := readFile(path)
+	if err != nil {
+		return index.Receipt{Status: index.ReceiptStatus{Source: index.SourceIndex{Name: constants.DefaultIndexName}}}, err
+	}
+	receipt, err := scheme.DecodeReceipt(b)
 	if receipt.Status.Source.Name == "" {
 		receipt.Status.Source.Name = constants.DefaultIndexName
 	}
-	return receipt, err
+	return receipt, errors.Wrapf(err, "failed to parse yaml file %q", path)
 }
 
-// readFromFile loads a file from the FS into the provided object.
-func readFromFile(path string, as interface{}) error {
+// readFile reads a manifest or receipt file. When the file is not found, it
+// returns an error that can be checked with os.IsNotExist.
+func readFile(path string) ([]byte, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return err
-	}
-	err = decodeFile(f, &as)
-	return errors.Wrapf(err, "failed to parse yaml file %q", path)
-}
-
-// decodeFile tries to decode a plugin/receipt
-func decodeFile(r io.ReadCloser, as interface{}) error {
-	defer r.Close()
-	b, err := ioutil.ReadAll(r)
-	if err != nil {
-		return err
+		return nil, err
 	}
-
-	// TODO(ahmetb): when we have a stable API that won't add new fields,
-	// we can consider failing on unknown fields. Currently, disabling due to
-	// incremental field additions to plugin manifests independently from the
-	// installed version of krew.
-	// yaml.UnmarshalStrict()
-	return yaml.Unmarshal(b, &as)
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	return b, errors.Wrapf(err, "failed to read file %q", path)
 }
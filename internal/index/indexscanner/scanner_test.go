@@ -190,6 +190,20 @@ func TestLoadIndexListFromFS(t *testing.T) {
 	}
 }
 
+func TestLoadPluginListWithFailures(t *testing.T) {
+	got, failed, err := LoadPluginListWithFailures(filepath.Join(testdataPath(t), "testindex", "plugins"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected 2 plugins, got %d", len(got))
+	}
+	want := []string{"badplugin", "badplugin2", "wrongname"}
+	if diff := cmp.Diff(want, failed); diff != "" {
+		t.Errorf("failed manifests mismatch: %s", diff)
+	}
+}
+
 func TestLoadIndexFileFromFS(t *testing.T) {
 	type args struct {
 		indexDir   string
@@ -251,3 +265,31 @@ func testdataPath(t *testing.T) string {
 	}
 	return filepath.Join(pwd, "testdata")
 }
+
+func TestReadPluginFromFile_v1beta1(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("foo.yaml", []byte(`apiVersion: krew.googlecontainertools.github.com/v1beta1
+kind: Plugin
+metadata:
+  name: foo
+spec:
+  version: v1.0.0
+  shortDescription: foo
+  deprecated: use bar instead
+  platforms:
+  - uri: https://example.com/foo.tar.gz
+    sha256: deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef
+    bin: foo.sh
+    selector:
+      matchLabels:
+        os: linux
+`))
+
+	p, err := ReadPluginFromFile(tmpDir.Path("foo.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Spec.Deprecated != "use bar instead" {
+		t.Errorf("expected deprecation message to be read, got %q", p.Spec.Deprecated)
+	}
+}
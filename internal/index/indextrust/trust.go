@@ -0,0 +1,195 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package indextrust verifies that the local copy of an index is signed by a
+// trusted key. Git indexes are verified by the OpenPGP signature of their HEAD
+// commit. Other indexes are verified by a snapshot manifest: a SHA256SUMS file
+// in the plugins directory that lists the checksum of every plugin manifest,
+// and its ASCII-armored detached signature SHA256SUMS.asc.
+package indextrust
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/pkg/constants"
+)
+
+// Verification methods.
+const (
+	MethodCommit   = "commit"
+	MethodSnapshot = "snapshot"
+)
+
+// Files of the snapshot manifest in the plugins directory of an index.
+const (
+	SnapshotManifest  = "SHA256SUMS"
+	SnapshotSignature = SnapshotManifest + ".asc"
+)
+
+// IsValidMethod tells whether m is a known verification method.
+func IsValidMethod(m string) bool {
+	return m == MethodCommit || m == MethodSnapshot
+}
+
+// ReadKeyRing reads the ASCII-armored OpenPGP public keys in the given files.
+func ReadKeyRing(files []string) (openpgp.EntityList, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no signing keys configured")
+	}
+	var keys openpgp.EntityList
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open signing key")
+		}
+		k, err := openpgp.ReadArmoredKeyRing(f)
+		f.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read signing key %q", file)
+		}
+		keys = append(keys, k...)
+	}
+	return keys, nil
+}
+
+// VerifyCommit checks that the HEAD commit of the git repository in dir is
+// signed by one of keys.
+func VerifyCommit(dir string, keys openpgp.EntityList) error {
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %q", dir)
+	}
+	head, err := r.Head()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get HEAD of %q", dir)
+	}
+	c, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return errors.Wrapf(err, "failed to read commit %s", head.Hash())
+	}
+	if c.PGPSignature == "" {
+		return errors.Errorf("commit %s is not signed", c.Hash)
+	}
+	encoded := &plumbing.MemoryObject{}
+	if err := c.EncodeWithoutSignature(encoded); err != nil {
+		return errors.Wrapf(err, "failed to encode commit %s", c.Hash)
+	}
+	content, err := encoded.Reader()
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode commit %s", c.Hash)
+	}
+	defer content.Close()
+	signer, err := openpgp.CheckArmoredDetachedSignature(keys, content, strings.NewReader(c.PGPSignature), nil)
+	if err != nil {
+		return errors.Wrapf(err, "signature of commit %s is not valid", c.Hash)
+	}
+	klog.V(2).Infof("Commit %s is signed by %s", c.Hash, describe(signer))
+	return nil
+}
+
+// VerifySnapshot checks that the snapshot manifest in pluginsDir is signed by
+// one of keys and matches the plugin manifests in pluginsDir. Manifests that
+// are not listed in the snapshot manifest fail the verification.
+func VerifySnapshot(pluginsDir string, keys openpgp.EntityList) error {
+	manifest, err := ioutil.ReadFile(filepath.Join(pluginsDir, SnapshotManifest))
+	if err != nil {
+		return errors.Wrap(err, "failed to read snapshot manifest")
+	}
+	sig, err := os.Open(filepath.Join(pluginsDir, SnapshotSignature))
+	if err != nil {
+		return errors.Wrap(err, "failed to read snapshot signature")
+	}
+	defer sig.Close()
+	signer, err := openpgp.CheckArmoredDetachedSignature(keys, bytes.NewReader(manifest), sig, nil)
+	if err != nil {
+		return errors.Wrap(err, "signature of snapshot manifest is not valid")
+	}
+	klog.V(2).Infof("Snapshot manifest in %q is signed by %s", pluginsDir, describe(signer))
+
+	sums, err := parseSums(manifest)
+	if err != nil {
+		return err
+	}
+	files, err := ioutil.ReadDir(pluginsDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to open index dir")
+	}
+	for _, f := range files {
+		if filepath.Ext(f.Name()) != constants.ManifestExtension {
+			continue
+		}
+		want, ok := sums[f.Name()]
+		if !ok {
+			return errors.Errorf("plugin manifest %q is not listed in the snapshot manifest", f.Name())
+		}
+		if got, err := fileSHA256(filepath.Join(pluginsDir, f.Name())); err != nil {
+			return err
+		} else if got != want {
+			return errors.Errorf("checksum of plugin manifest %q does not match the snapshot manifest", f.Name())
+		}
+	}
+	return nil
+}
+
+// parseSums parses a manifest in the format of sha256sum.
+func parseSums(b []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, errors.Errorf("invalid line in snapshot manifest: %q", line)
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums, errors.Wrap(scanner.Err(), "failed to read snapshot manifest")
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.Wrapf(err, "failed to read %q", path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func describe(e *openpgp.Entity) string {
+	for name := range e.Identities {
+		return name
+	}
+	return e.PrimaryKey.KeyIdString()
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indextrust
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"sigs.k8s.io/krew/internal/testutil"
+)
+
+func newKey(t *testing.T, tmpDir *testutil.TempDir, name string) (*openpgp.Entity, string) {
+	t.Helper()
+	e, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tmpDir.Write(name+".asc", buf.Bytes())
+	return e, tmpDir.Path(name + ".asc")
+}
+
+func commit(t *testing.T, dir string, signKey *openpgp.Entity) {
+	t.Helper()
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add("plugins"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Commit("add plugins", &git.CommitOptions{
+		Author:  &object.Signature{Name: "krew", Email: "krew@example.com", When: time.Now()},
+		SignKey: signKey,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyCommit(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	trusted, trustedFile := newKey(t, tmpDir, "trusted")
+	other, _ := newKey(t, tmpDir, "other")
+	keys, err := ReadKeyRing([]string{trustedFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		signKey *openpgp.Entity
+		wantErr bool
+	}{
+		{name: "trusted", signKey: trusted},
+		{name: "other", signKey: other, wantErr: true},
+		{name: "unsigned", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tmpDir.Path("repo-" + tt.name)
+			tmpDir.Write("repo-"+tt.name+"/plugins/foo.yaml", []byte("foo"))
+			commit(t, dir, tt.signKey)
+			if err := VerifyCommit(dir, keys); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyCommit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func writeSnapshot(t *testing.T, tmpDir *testutil.TempDir, dir string, key *openpgp.Entity, files map[string]string) {
+	t.Helper()
+	var sums bytes.Buffer
+	for name, content := range files {
+		sum := sha256.Sum256([]byte(content))
+		fmt.Fprintf(&sums, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+		tmpDir.Write(dir+"/"+name, []byte(content))
+	}
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, key, bytes.NewReader(sums.Bytes()), nil); err != nil {
+		t.Fatal(err)
+	}
+	tmpDir.Write(dir+"/"+SnapshotManifest, sums.Bytes())
+	tmpDir.Write(dir+"/"+SnapshotSignature, sig.Bytes())
+}
+
+func TestVerifySnapshot(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	trusted, trustedFile := newKey(t, tmpDir, "trusted")
+	other, _ := newKey(t, tmpDir, "other")
+	keys, err := ReadKeyRing([]string{trustedFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{"foo.yaml": "foo", "bar.yaml": "bar"}
+
+	writeSnapshot(t, tmpDir, "valid", trusted, files)
+	if err := VerifySnapshot(tmpDir.Path("valid"), keys); err != nil {
+		t.Errorf("VerifySnapshot() on valid snapshot: %v", err)
+	}
+
+	writeSnapshot(t, tmpDir, "other", other, files)
+	if err := VerifySnapshot(tmpDir.Path("other"), keys); err == nil {
+		t.Error("expected error for snapshot signed by an untrusted key")
+	}
+
+	writeSnapshot(t, tmpDir, "modified", trusted, files)
+	tmpDir.Write("modified/foo.yaml", []byte("evil"))
+	if err := VerifySnapshot(tmpDir.Path("modified"), keys); err == nil {
+		t.Error("expected error for modified manifest")
+	}
+
+	writeSnapshot(t, tmpDir, "added", trusted, files)
+	tmpDir.Write("added/evil.yaml", []byte("evil"))
+	if err := VerifySnapshot(tmpDir.Path("added"), keys); err == nil {
+		t.Error("expected error for manifest not in the snapshot")
+	}
+
+	tmpDir.Write("unsigned/foo.yaml", []byte("foo"))
+	if err := VerifySnapshot(tmpDir.Path("unsigned"), keys); err == nil {
+		t.Error("expected error for index without snapshot manifest")
+	}
+}
+
+func TestReadKeyRing_errors(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("invalid.asc", []byte("not a key"))
+	for _, files := range [][]string{nil, {tmpDir.Path("missing.asc")}, {tmpDir.Path("invalid.asc")}} {
+		if _, err := ReadKeyRing(files); err == nil {
+			t.Errorf("ReadKeyRing(%q) expected error", files)
+		}
+	}
+}
@@ -25,6 +25,9 @@ import (
 	"sigs.k8s.io/krew/internal/installation/semver"
 	"sigs.k8s.io/krew/pkg/constants"
 	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/scheme"
+	"sigs.k8s.io/krew/pkg/index/v1alpha2"
+	"sigs.k8s.io/krew/pkg/index/v1beta1"
 )
 
 const (
@@ -55,7 +58,7 @@ func IsSafePluginName(name string) bool {
 }
 
 func isSupportedAPIVersion(apiVersion string) bool {
-	return apiVersion == constants.CurrentAPIVersion
+	return scheme.IsSupportedAPIVersion(apiVersion)
 }
 
 func isValidSHA256(s string) bool { return validSHA256.MatchString(s) }
@@ -82,6 +85,17 @@ func ValidatePlugin(name string, p index.Plugin) error {
 	if strings.ContainsAny(p.Spec.ShortDescription, "\r\n") {
 		return errors.New("should not have line breaks in short description")
 	}
+	if strings.ContainsAny(p.Spec.Deprecated, "\r\n") {
+		return errors.New("should not have line breaks in deprecation message")
+	}
+	if p.Spec.ReplacedBy != "" {
+		if !IsSafePluginName(p.Spec.ReplacedBy) {
+			return errors.Errorf("the replacement plugin name %q is not allowed, must match %q", p.Spec.ReplacedBy, safePluginRegexp.String())
+		}
+		if p.Spec.ReplacedBy == name {
+			return errors.New("should not be replaced by itself")
+		}
+	}
 	if len(p.Spec.Platforms) == 0 {
 		return errors.New("should have a platform specified")
 	}
@@ -92,15 +106,43 @@ func ValidatePlugin(name string, p index.Plugin) error {
 		return errors.Wrap(err, "failed to parse plugin version")
 	}
 	for _, pl := range p.Spec.Platforms {
-		if err := validatePlatform(pl); err != nil {
+		if err := validatePlatform(pl, p.APIVersion); err != nil {
 			return errors.Wrapf(err, "platform (%+v) is badly constructed", pl)
 		}
+		for _, e := range pl.Executables {
+			if binName(e.Name) == binName(name) {
+				return errors.Errorf("executable %q collides with the plugin name", e.Name)
+			}
+		}
 	}
 	return nil
 }
 
-// validatePlatform checks Platform for structural validity.
-func validatePlatform(p index.Platform) error {
+// binName normalizes a plugin name to the name of its executable. Dashes are
+// converted to underscores, so "foo-bar" and "foo_bar" are the same executable.
+func binName(name string) string { return strings.ReplaceAll(name, "-", "_") }
+
+// validateExecutables checks the additional executables of a platform.
+func validateExecutables(executables []index.Executable) error {
+	seen := make(map[string]bool, len(executables))
+	for _, e := range executables {
+		if !IsSafePluginName(e.Name) {
+			return errors.Errorf("executable name %q is not allowed, must match %q", e.Name, safePluginRegexp.String())
+		}
+		if e.Bin == "" {
+			return errors.Errorf("`bin` of executable %q has to be set", e.Name)
+		}
+		if seen[binName(e.Name)] {
+			return errors.Errorf("executable %q is specified more than once", e.Name)
+		}
+		seen[binName(e.Name)] = true
+	}
+	return nil
+}
+
+// validatePlatform checks Platform of a manifest with the given apiVersion
+// for structural validity.
+func validatePlatform(p index.Platform, apiVersion string) error {
 	if p.URI == "" {
 		return errors.New("`uri` has to be set")
 	}
@@ -116,9 +158,12 @@ func validatePlatform(p index.Platform) error {
 	if err := validateFiles(p.Files); err != nil {
 		return errors.Wrap(err, "`files` is invalid")
 	}
-	if err := validateSelector(p.Selector); err != nil {
+	if err := validateSelector(p.Selector, apiVersion); err != nil {
 		return errors.Wrap(err, "invalid platform selector")
 	}
+	if err := validateExecutables(p.Executables); err != nil {
+		return errors.Wrap(err, "`executables` is invalid")
+	}
 	return nil
 }
 
@@ -139,8 +184,25 @@ func validateFiles(fops []index.FileOperation) error {
 	return nil
 }
 
-// validateSelector checks if the platform selector uses supported keys and is not empty or nil.
-func validateSelector(sel *metav1.LabelSelector) error {
+// selectorKeys are the labels that platform selectors can match against.
+var selectorKeys = map[string]bool{
+	"os":   true,
+	"arch": true,
+	"libc": true, // "glibc" or "musl" on Linux
+	"arm":  true, // variant of 32-bit ARM, e.g. "v6" or "v7"
+}
+
+// v1alpha2SelectorKeys are the labels that v1alpha2 manifests can match
+// against. The v1alpha2 schema is frozen, since older versions of krew reject
+// manifests with other keys.
+var v1alpha2SelectorKeys = map[string]bool{
+	"os":   true,
+	"arch": true,
+}
+
+// validateSelector checks if the platform selector uses keys supported by the
+// apiVersion of its manifest and is not empty or nil.
+func validateSelector(sel *metav1.LabelSelector, apiVersion string) error {
 	if sel == nil {
 		return errors.New("nil selector is not supported")
 	}
@@ -157,9 +219,12 @@ func validateSelector(sel *metav1.LabelSelector) error {
 		keys = append(keys, expr.Key)
 	}
 	for _, key := range keys {
-		if key != "os" && key != "arch" {
+		if !selectorKeys[key] {
 			return errors.Errorf("key %q not supported", key)
 		}
+		if apiVersion == v1alpha2.APIVersion && !v1alpha2SelectorKeys[key] {
+			return errors.Errorf("key %q requires apiVersion %s", key, v1beta1.APIVersion)
+		}
 	}
 
 	if sel.MatchLabels != nil && len(sel.MatchLabels) == 0 {
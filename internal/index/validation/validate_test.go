@@ -22,6 +22,8 @@ import (
 	"sigs.k8s.io/krew/internal/testutil"
 	"sigs.k8s.io/krew/pkg/constants"
 	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/v1alpha2"
+	"sigs.k8s.io/krew/pkg/index/v1beta1"
 )
 
 func Test_IsSafePluginName(t *testing.T) {
@@ -71,6 +73,7 @@ func Test_isSupportedAPIVersion(t *testing.T) {
 		{"old version", "krew.googlecontainertools.github.com/v1alpha1", false},
 		{"equal version", "krew.googlecontainertools.github.com/v1alpha2", true},
 		{"newer 1", "krew.googlecontainertools.github.com/v1alpha3", false},
+		{"beta version", "krew.googlecontainertools.github.com/v1beta1", true},
 		{"newer 2", "krew.googlecontainertools.github.com/v1", false},
 		{"newer 2", "krew.googlecontainertools.github.com/v2alpha1", false},
 	}
@@ -96,6 +99,23 @@ func TestValidatePlugin(t *testing.T) {
 			plugin:     testutil.NewPlugin().WithName("foo").V(),
 			wantErr:    false,
 		},
+		{
+			name:       "libc selector in v1alpha2",
+			pluginName: "foo",
+			plugin: testutil.NewPlugin().WithName("foo").WithPlatforms(testutil.NewPlatform().WithSelector(
+				&metav1.LabelSelector{MatchLabels: map[string]string{"os": "linux", "libc": "musl"}}).V()).V(),
+			wantErr: true,
+		},
+		{
+			name:       "libc selector in v1beta1",
+			pluginName: "foo",
+			plugin: testutil.NewPlugin().WithName("foo").WithTypeMeta(metav1.TypeMeta{
+				APIVersion: v1beta1.APIVersion,
+				Kind:       constants.PluginKind,
+			}).WithPlatforms(testutil.NewPlatform().WithSelector(
+				&metav1.LabelSelector{MatchLabels: map[string]string{"os": "linux", "libc": "musl"}}).V()).V(),
+			wantErr: false,
+		},
 		{
 			name:       "file name mismatch",
 			pluginName: "orange",
@@ -176,6 +196,37 @@ func TestValidatePlugin(t *testing.T) {
 			plugin:     testutil.NewPlugin().WithShortDescription("just\r\nfoo").V(),
 			wantErr:    true,
 		},
+		{
+			name:       "deprecated and replaced",
+			pluginName: "foo",
+			plugin:     testutil.NewPlugin().WithName("foo").WithDeprecated("renamed to bar").WithReplacedBy("bar").V(),
+			wantErr:    false,
+		},
+		{
+			name:       "deprecation message with line break",
+			pluginName: "foo",
+			plugin:     testutil.NewPlugin().WithName("foo").WithDeprecated("no longer\nmaintained").V(),
+			wantErr:    true,
+		},
+		{
+			name:       "unsafe replacement name",
+			pluginName: "foo",
+			plugin:     testutil.NewPlugin().WithName("foo").WithReplacedBy("../bar").V(),
+			wantErr:    true,
+		},
+		{
+			name:       "replaced by itself",
+			pluginName: "foo",
+			plugin:     testutil.NewPlugin().WithName("foo").WithReplacedBy("foo").V(),
+			wantErr:    true,
+		},
+		{
+			name:       "executable named like the plugin",
+			pluginName: "foo-bar",
+			plugin: testutil.NewPlugin().WithName("foo-bar").WithPlatforms(testutil.NewPlatform().WithExecutables(
+				index.Executable{Name: "foo_bar", Bin: "bar"}).V()).V(),
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -217,6 +268,30 @@ func TestValidatePlatform(t *testing.T) {
 			platform: testutil.NewPlatform().WithBin("").V(),
 			wantErr:  true,
 		},
+		{
+			name: "executables",
+			platform: testutil.NewPlatform().WithExecutables(
+				index.Executable{Name: "foo-bar", Bin: "bar"},
+				index.Executable{Name: "foo-baz", Bin: "baz"}).V(),
+			wantErr: false,
+		},
+		{
+			name:     "unsafe executable name",
+			platform: testutil.NewPlatform().WithExecutables(index.Executable{Name: "../bar", Bin: "bar"}).V(),
+			wantErr:  true,
+		},
+		{
+			name:     "executable without bin",
+			platform: testutil.NewPlatform().WithExecutables(index.Executable{Name: "foo-bar"}).V(),
+			wantErr:  true,
+		},
+		{
+			name: "duplicate executables",
+			platform: testutil.NewPlatform().WithExecutables(
+				index.Executable{Name: "foo-bar", Bin: "bar"},
+				index.Executable{Name: "foo_bar", Bin: "baz"}).V(),
+			wantErr: true,
+		},
 		{
 			name: "invalid platform selector",
 			platform: testutil.NewPlatform().WithSelector(&metav1.LabelSelector{
@@ -229,7 +304,7 @@ func TestValidatePlatform(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := validatePlatform(tt.platform); (err != nil) != tt.wantErr {
+			if err := validatePlatform(tt.platform, v1beta1.APIVersion); (err != nil) != tt.wantErr {
 				t.Errorf("validatePlatform() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -238,9 +313,10 @@ func TestValidatePlatform(t *testing.T) {
 
 func Test_validateSelector(t *testing.T) {
 	var tests = []struct {
-		name    string
-		sel     *metav1.LabelSelector
-		wantErr bool
+		name       string
+		sel        *metav1.LabelSelector
+		apiVersion string
+		wantErr    bool
 	}{
 		{
 			name:    "nil selector",
@@ -267,6 +343,44 @@ func Test_validateSelector(t *testing.T) {
 					}}},
 			wantErr: false,
 		},
+		{
+			name:    "valid libc and arm matchLabels",
+			sel:     &metav1.LabelSelector{MatchLabels: map[string]string{"os": "linux", "arch": "arm", "arm": "v7", "libc": "musl"}},
+			wantErr: false,
+		},
+		{
+			name: "valid libc matchExpressions",
+			sel: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "libc",
+						Operator: metav1.LabelSelectorOpNotIn,
+						Values:   []string{"musl"},
+					}}},
+			wantErr: false,
+		},
+		{
+			name:       "libc matchLabels in v1alpha2",
+			sel:        &metav1.LabelSelector{MatchLabels: map[string]string{"os": "linux", "libc": "musl"}},
+			apiVersion: v1alpha2.APIVersion,
+			wantErr:    true,
+		},
+		{
+			name: "arm matchExpressions in v1alpha2",
+			sel: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "arm",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"v7"},
+					}}},
+			apiVersion: v1alpha2.APIVersion,
+			wantErr:    true,
+		},
+		{
+			name:       "os and arch in v1alpha2",
+			sel:        &metav1.LabelSelector{MatchLabels: map[string]string{"os": "linux", "arch": "amd64"}},
+			apiVersion: v1alpha2.APIVersion,
+			wantErr:    false,
+		},
 		{
 			name:    "empty matchLabels",
 			sel:     &metav1.LabelSelector{MatchLabels: map[string]string{}},
@@ -294,7 +408,11 @@ func Test_validateSelector(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := validateSelector(tt.sel); (err != nil) != tt.wantErr {
+			apiVersion := tt.apiVersion
+			if apiVersion == "" {
+				apiVersion = v1beta1.APIVersion
+			}
+			if err := validateSelector(tt.sel, apiVersion); (err != nil) != tt.wantErr {
 				t.Errorf("validateSelector() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -0,0 +1,90 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package indexconfigmigration generates the index configuration file from
+// the indexes cloned by krew versions that did not have one.
+package indexconfigmigration
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/index/indexbackend"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
+)
+
+// Done checks if the krew installation requires a migration to the index
+// configuration file. A migration is necessary when the file does not exist.
+func Done(paths environment.Paths) (bool, error) {
+	klog.V(2).Info("Checking if index configuration migration is needed.")
+	_, err := os.Stat(paths.IndexConfigPath())
+	if err == nil {
+		klog.V(2).Infoln("Index configuration already migrated.")
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// Migrate writes the index configuration file with an entry for each index
+// directory in the index base directory.
+func Migrate(paths environment.Paths) error {
+	klog.V(1).Info("Migrating indexes to the index configuration file.")
+	entries, err := ioutil.ReadDir(paths.IndexBase())
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to list index directory")
+	}
+
+	cfg := indexconfig.New()
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		name, dir := e.Name(), paths.IndexPath(e.Name())
+		backend, err := indexbackend.ForDir(dir)
+		if os.IsNotExist(errors.Cause(err)) {
+			klog.Warningf("Skipping %q, it is not an index", dir)
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to determine the type of index %s", name)
+		}
+		url, err := backend.URL(dir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the URL of index %s", name)
+		}
+		ref, err := backend.Ref(dir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the pinned ref of index %s", name)
+		}
+		klog.V(2).Infof("Adding index %q (%s) to the index configuration", name, url)
+		cfg.Indexes = append(cfg.Indexes, indexconfig.Entry{
+			Name: name,
+			URL:  url,
+			Ref:  ref,
+			Type: backend.Type(),
+		})
+	}
+
+	if err := indexconfig.Save(paths.IndexConfigPath(), cfg); err != nil {
+		return err
+	}
+	klog.V(1).Info("Index configuration migration completed successfully.")
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexconfigmigration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
+	"sigs.k8s.io/krew/internal/testutil"
+)
+
+func TestMigrate(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	paths := environment.NewPaths(tmpDir.Root())
+	tmpDir.InitEmptyGitRepo(paths.IndexPath("default"), "https://github.com/kubernetes-sigs/krew-index.git")
+	tmpDir.InitEmptyGitRepo(paths.IndexPath("foo"), "https://github.com/foo/index.git")
+	tmpDir.Write("index/not-an-index/file", nil)
+
+	if done, err := Done(paths); err != nil || done {
+		t.Fatalf("Done() = %v, %v; expected migration to be needed", done, err)
+	}
+	if err := Migrate(paths); err != nil {
+		t.Fatal(err)
+	}
+	if done, err := Done(paths); err != nil || !done {
+		t.Fatalf("Done() = %v, %v; expected migration to be done", done, err)
+	}
+
+	cfg, err := indexconfig.Load(paths.IndexConfigPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []indexconfig.Entry{
+		{Name: "default", URL: "https://github.com/kubernetes-sigs/krew-index.git", Type: "git"},
+		{Name: "foo", URL: "https://github.com/foo/index.git", Type: "git"},
+	}
+	if diff := cmp.Diff(want, cfg.Indexes); diff != "" {
+		t.Errorf("migrated configuration does not match: %s", diff)
+	}
+}
+
+func TestMigrate_noIndexes(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	paths := environment.NewPaths(tmpDir.Root())
+	if err := Migrate(paths); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := indexconfig.Load(paths.IndexConfigPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Indexes) != 0 {
+		t.Errorf("expected no indexes, got %v", cfg.Indexes)
+	}
+}
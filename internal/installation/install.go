@@ -27,8 +27,10 @@ import (
 
 	"sigs.k8s.io/krew/internal/download"
 	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
 	"sigs.k8s.io/krew/internal/installation/receipt"
 	"sigs.k8s.io/krew/internal/pathutil"
+	"sigs.k8s.io/krew/internal/policy"
 	"sigs.k8s.io/krew/pkg/constants"
 	"sigs.k8s.io/krew/pkg/index"
 )
@@ -36,6 +38,10 @@ import (
 // InstallOpts specifies options for plugin installation operation.
 type InstallOpts struct {
 	ArchiveFileOverride string
+	// Detached tells that the manifest was not read from the index it is
+	// installed for, such as a manifest file or a bundle. The policy then
+	// treats it as coming from an unsigned index.
+	Detached bool
 }
 
 type installOperation struct {
@@ -72,6 +78,12 @@ func Install(p environment.Paths, plugin index.Plugin, indexName string, opts In
 	if !ok {
 		return errors.Errorf("plugin %q does not offer installation for this platform", plugin.Name)
 	}
+	if err := checkPolicy(p, plugin, indexName, candidate, opts.Detached); err != nil {
+		return err
+	}
+	if err := checkExecutables(p, plugin.Name, candidate); err != nil {
+		return err
+	}
 
 	// The actual install should be the last action so that a failure during receipt
 	// saving does not result in an installed plugin without receipt.
@@ -87,7 +99,9 @@ func Install(p environment.Paths, plugin index.Plugin, indexName string, opts In
 	}
 
 	klog.V(3).Infof("Storing install receipt for plugin %s", plugin.Name)
-	err = receipt.Store(receipt.New(plugin, indexName, metav1.Now()), p.PluginInstallReceiptPath(plugin.Name))
+	r := receipt.New(plugin, indexName, metav1.Now())
+	r.Status.Executables = executableNames(candidate)
+	err = receipt.Store(r, p.PluginInstallReceiptPath(plugin.Name))
 	return errors.Wrap(err, "installation receipt could not be stored, uninstall may fail")
 }
 
@@ -114,20 +128,146 @@ func install(op installOperation, opts InstallOpts) error {
 		return errors.Wrap(err, "failed while moving files to the installation directory")
 	}
 
-	subPathAbs, err := filepath.Abs(op.installDir)
+	if err := linkExecutable(op.binDir, op.installDir, op.pluginName, op.platform.Bin, op.platform.Completion); err != nil {
+		return errors.Wrap(err, "failed to link installed plugin")
+	}
+	for _, e := range op.platform.Executables {
+		if err := linkExecutable(op.binDir, op.installDir, e.Name, e.Bin, e.Completion); err != nil {
+			return errors.Wrapf(err, "failed to link executable %q", e.Name)
+		}
+	}
+	return nil
+}
+
+// linkExecutable links the executable bin in installDir as the plugin name,
+// and its completion executable, if any, as the completion of the plugin. A
+// completion link left from a previous installation is removed if there is
+// no completion anymore.
+func linkExecutable(binDir, installDir, name, bin, completion string) error {
+	fullPath, err := binaryPath(installDir, bin)
+	if err != nil {
+		return err
+	}
+	if err := createOrUpdateLink(binDir, fullPath, name); err != nil {
+		return err
+	}
+	if completion == "" {
+		return removeLink(filepath.Join(binDir, completionNameToBin(name, IsWindows())))
+	}
+	fullPath, err = binaryPath(installDir, completion)
+	if err != nil {
+		return err
+	}
+	return updateLink(fullPath, filepath.Join(binDir, completionNameToBin(name, IsWindows())))
+}
+
+// binaryPath returns the path of the executable bin in installDir. It is an
+// error if the path is outside of installDir.
+func binaryPath(installDir, bin string) (string, error) {
+	subPathAbs, err := filepath.Abs(installDir)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get the absolute fullPath of %q", op.installDir)
+		return "", errors.Wrapf(err, "failed to get the absolute fullPath of %q", installDir)
 	}
-	fullPath := filepath.Join(op.installDir, filepath.FromSlash(op.platform.Bin))
+	fullPath := filepath.Join(installDir, filepath.FromSlash(bin))
 	pathAbs, err := filepath.Abs(fullPath)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get the absolute fullPath of %q", fullPath)
+		return "", errors.Wrapf(err, "failed to get the absolute fullPath of %q", fullPath)
 	}
 	if _, ok := pathutil.IsSubPath(subPathAbs, pathAbs); !ok {
-		return errors.Wrapf(err, "the fullPath %q does not extend the sub-fullPath %q", fullPath, op.installDir)
+		return "", errors.Errorf("the fullPath %q does not extend the sub-fullPath %q", fullPath, installDir)
 	}
-	err = createOrUpdateLink(op.binDir, fullPath, op.pluginName)
-	return errors.Wrap(err, "failed to link installed plugin")
+	return fullPath, nil
+}
+
+// executableNames returns the names of the additional executables of a
+// platform.
+func executableNames(platform index.Platform) []string {
+	var names []string
+	for _, e := range platform.Executables {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// checkExecutables makes sure that the plugin and its additional executables
+// do not collide with other installed plugins or their executables.
+func checkExecutables(p environment.Paths, pluginName string, platform index.Platform) error {
+	receipts, err := GetInstalledPluginReceipts(p.InstallReceiptsPath())
+	if err != nil {
+		return errors.Wrap(err, "failed to load installed plugins")
+	}
+	owners := make(map[string]string)
+	for _, r := range receipts {
+		if r.Name == pluginName {
+			continue
+		}
+		owners[pluginNameToBin(r.Name, false)] = r.Name
+		for _, e := range r.Status.Executables {
+			owners[pluginNameToBin(e, false)] = r.Name
+		}
+	}
+	for _, name := range append([]string{pluginName}, executableNames(platform)...) {
+		if owner, ok := owners[pluginNameToBin(name, false)]; ok {
+			return errors.Errorf("executable %q of plugin %q collides with installed plugin %q", name, pluginName, owner)
+		}
+	}
+	return nil
+}
+
+// removeStaleLinks removes the links of the executables in old that are not
+// in current.
+func removeStaleLinks(p environment.Paths, old, current []string) error {
+	keep := make(map[string]bool, len(current))
+	for _, name := range current {
+		keep[pluginNameToBin(name, false)] = true
+	}
+	for _, name := range old {
+		if keep[pluginNameToBin(name, false)] {
+			continue
+		}
+		klog.V(3).Infof("Unlink executable %q", name)
+		if err := removeLink(PluginBinPath(p, name)); err != nil {
+			return errors.Wrapf(err, "could not remove symlink of executable %q", name)
+		}
+		if err := removeLink(completionBinPath(p, name)); err != nil {
+			return errors.Wrapf(err, "could not remove completion symlink of executable %q", name)
+		}
+	}
+	return nil
+}
+
+// checkPolicy checks that the policy allows installing the archive of the
+// given platform of a plugin from an index. Detached manifests are not vouched
+// for by the index, so they are checked as coming from an unsigned index.
+func checkPolicy(p environment.Paths, plugin index.Plugin, indexName string, platform index.Platform, detached bool) error {
+	pol, err := policy.Load()
+	if err != nil {
+		return errors.Wrap(err, "failed to load policy")
+	}
+	signed := false
+	if !detached {
+		url, trusted := indexSource(p, indexName)
+		if err := pol.CheckIndex(url, trusted); err != nil {
+			return err
+		}
+		signed = trusted
+	}
+	return pol.CheckPlugin(indexName, plugin.Name, signed, platform.Sha256)
+}
+
+// indexSource returns the configured URL of an index and whether it is
+// configured with signing keys.
+func indexSource(p environment.Paths, indexName string) (url string, signed bool) {
+	cfg, err := indexconfig.Load(p.IndexConfigPath())
+	if err == nil {
+		if e, ok := cfg.Find(indexName); ok {
+			return e.URL, e.Trust != nil
+		}
+	}
+	if indexName == constants.DefaultIndexName {
+		return index.DefaultIndex(), false
+	}
+	return "", false
 }
 
 func applyDefaults(platform *index.Platform) {
@@ -162,7 +302,8 @@ func Uninstall(p environment.Paths, name string) error {
 	}
 	klog.V(3).Infof("Finding installed version to delete")
 
-	if _, err := receipt.Load(p.PluginInstallReceiptPath(name)); err != nil {
+	r, err := receipt.Load(p.PluginInstallReceiptPath(name))
+	if err != nil {
 		if os.IsNotExist(err) {
 			return ErrIsNotInstalled
 		}
@@ -171,11 +312,17 @@ func Uninstall(p environment.Paths, name string) error {
 
 	klog.V(1).Infof("Deleting plugin %s", name)
 
-	symlinkPath := filepath.Join(p.BinPath(), pluginNameToBin(name, IsWindows()))
+	symlinkPath := PluginBinPath(p, name)
 	klog.V(3).Infof("Unlink %q", symlinkPath)
 	if err := removeLink(symlinkPath); err != nil {
 		return errors.Wrap(err, "could not uninstall symlink of plugin")
 	}
+	if err := removeLink(completionBinPath(p, name)); err != nil {
+		return errors.Wrap(err, "could not uninstall completion symlink of plugin")
+	}
+	if err := removeStaleLinks(p, r.Status.Executables, nil); err != nil {
+		return err
+	}
 
 	pluginInstallPath := p.PluginInstallPath(name)
 	klog.V(3).Infof("Deleting path %q", pluginInstallPath)
@@ -184,13 +331,16 @@ func Uninstall(p environment.Paths, name string) error {
 	}
 	pluginReceiptPath := p.PluginInstallReceiptPath(name)
 	klog.V(3).Infof("Deleting plugin receipt %q", pluginReceiptPath)
-	err := os.Remove(pluginReceiptPath)
+	err = os.Remove(pluginReceiptPath)
 	return errors.Wrapf(err, "could not remove plugin receipt %q", pluginReceiptPath)
 }
 
 func createOrUpdateLink(binDir, binary, plugin string) error {
-	dst := filepath.Join(binDir, pluginNameToBin(plugin, IsWindows()))
+	return updateLink(binary, filepath.Join(binDir, pluginNameToBin(plugin, IsWindows())))
+}
 
+// updateLink replaces the link at dst with a symlink to binary.
+func updateLink(binary, dst string) error {
 	if err := removeLink(dst); err != nil {
 		return errors.Wrap(err, "failed to remove old symlink")
 	}
@@ -237,6 +387,28 @@ func IsWindows() bool {
 	return goos == "windows"
 }
 
+// PluginBinPath returns the path of the symlink that is created in the bin
+// directory for the plugin with the given name.
+func PluginBinPath(p environment.Paths, name string) string {
+	return filepath.Join(p.BinPath(), pluginNameToBin(name, IsWindows()))
+}
+
+// completionBinPath returns the path of the symlink to the completion
+// executable of a plugin that is created in the bin directory.
+func completionBinPath(p environment.Paths, name string) string {
+	return filepath.Join(p.BinPath(), completionNameToBin(name, IsWindows()))
+}
+
+// completionNameToBin creates the name of the symlink file for the completion
+// of the plugin name, which kubectl looks up to complete plugin arguments.
+func completionNameToBin(name string, isWindows bool) string {
+	name = "kubectl_complete-" + strings.ReplaceAll(name, "-", "_")
+	if isWindows {
+		name += ".exe"
+	}
+	return name
+}
+
 // pluginNameToBin creates the name of the symlink file for the plugin name.
 // It converts dashes to underscores.
 func pluginNameToBin(name string, isWindows bool) string {
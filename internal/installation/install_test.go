@@ -26,10 +26,16 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/index/indexconfig"
+	"sigs.k8s.io/krew/internal/installation/receipt"
+	"sigs.k8s.io/krew/internal/policy"
 	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/constants"
 	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/v1beta1"
 )
 
 func Test_moveTargets(t *testing.T) {
@@ -135,6 +141,56 @@ func Test_pluginNameToBin(t *testing.T) {
 	}
 }
 
+func Test_completionNameToBin(t *testing.T) {
+	tests := []struct {
+		name      string
+		isWindows bool
+		want      string
+	}{
+		{"foo", false, "kubectl_complete-foo"},
+		{"foo-bar", false, "kubectl_complete-foo_bar"},
+		{"foo-bar", true, "kubectl_complete-foo_bar.exe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completionNameToBin(tt.name, tt.isWindows); got != tt.want {
+				t.Errorf("completionNameToBin(%v, %v) = %v; want %v", tt.name, tt.isWindows, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_linkExecutable_completion(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("install/foo.sh", nil)
+	tmpDir.Write("install/complete.sh", nil)
+	binDir := tmpDir.Path("bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	completionLink := filepath.Join(binDir, completionNameToBin("foo-bar", IsWindows()))
+
+	if err := linkExecutable(binDir, tmpDir.Path("install"), "foo-bar", "foo.sh", "complete.sh"); err != nil {
+		t.Fatal(err)
+	}
+	if target, err := os.Readlink(completionLink); err != nil {
+		t.Fatal(err)
+	} else if target != tmpDir.Path("install/complete.sh") {
+		t.Errorf("completion link points to %q", target)
+	}
+
+	if err := linkExecutable(binDir, tmpDir.Path("install"), "foo-bar", "foo.sh", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(completionLink); !os.IsNotExist(err) {
+		t.Errorf("expected completion link to be removed, got err=%v", err)
+	}
+
+	if err := linkExecutable(binDir, tmpDir.Path("install"), "foo-bar", "foo.sh", "../outside.sh"); err == nil {
+		t.Error("expected error for completion outside of the installation directory")
+	}
+}
+
 func Test_removeLink_notExists(t *testing.T) {
 	if err := removeLink("/non/existing/path"); err != nil {
 		t.Fatalf("removeLink failed with non-existing path: %+v", err)
@@ -328,3 +384,115 @@ func TestCleanupStaleKrewInstallations(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+func Test_checkPolicy(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("policy.yaml", []byte(`apiVersion: policy.krew.sigs.k8s.io/v1alpha1
+kind: Policy
+requireSignedIndexes: true
+allowedIndexURLs:
+- https://git.example.com/*
+deniedPlugins:
+- bar
+`))
+	os.Setenv(policy.Env, tmpDir.Path("policy.yaml"))
+	defer os.Unsetenv(policy.Env)
+
+	p := environment.NewPaths(tmpDir.Root())
+	cfg := indexconfig.New()
+	cfg.Indexes = []indexconfig.Entry{
+		{Name: "signed", URL: "https://git.example.com/signed.git", Trust: &indexconfig.Trust{SigningKeys: []string{"key.asc"}}},
+		{Name: "unsigned", URL: "https://git.example.com/unsigned.git"},
+		{Name: "elsewhere", URL: "https://github.com/evil/index.git", Trust: &indexconfig.Trust{SigningKeys: []string{"key.asc"}}},
+	}
+	if err := indexconfig.Save(p.IndexConfigPath(), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	platform := testutil.NewPlatform().V()
+	tests := []struct {
+		index, plugin string
+		detached      bool
+		wantErr       bool
+	}{
+		{index: "signed", plugin: "foo"},
+		{index: "signed", plugin: "bar", wantErr: true},
+		{index: "signed", plugin: "foo", detached: true, wantErr: true},
+		{index: "unsigned", plugin: "foo", wantErr: true},
+		{index: "elsewhere", plugin: "foo", wantErr: true},
+		{index: "detached", plugin: "foo", detached: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := checkPolicy(p, testutil.NewPlugin().WithName(tt.plugin).V(), tt.index, platform, tt.detached)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkPolicy(%s/%s) error = %v, wantErr %v", tt.index, tt.plugin, err, tt.wantErr)
+		}
+		if err != nil && !policy.IsViolation(err) {
+			t.Errorf("expected a policy violation, got %v", err)
+		}
+	}
+}
+
+func Test_checkExecutables(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+	installed := testutil.NewReceipt().
+		WithPlugin(testutil.NewPlugin().WithName("foo").
+			WithTypeMeta(metav1.TypeMeta{APIVersion: v1beta1.APIVersion, Kind: constants.PluginKind}).V()).
+		WithStatus(index.ReceiptStatus{Executables: []string{"foo-helper"}}).V()
+	if err := os.MkdirAll(p.InstallReceiptsPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := receipt.Store(installed, p.PluginInstallReceiptPath("foo")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		plugin      string
+		executables []string
+		wantErr     bool
+	}{
+		{name: "no collision", plugin: "bar", executables: []string{"bar-helper"}},
+		{name: "reinstalling itself", plugin: "foo", executables: []string{"foo-helper"}},
+		{name: "executable named like plugin", plugin: "bar", executables: []string{"foo"}, wantErr: true},
+		{name: "executable named like executable", plugin: "bar", executables: []string{"foo_helper"}, wantErr: true},
+		{name: "plugin named like executable", plugin: "foo-helper", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platform := testutil.NewPlatform().V()
+			for _, e := range tt.executables {
+				platform.Executables = append(platform.Executables, index.Executable{Name: e, Bin: e})
+			}
+			err := checkExecutables(p, tt.plugin, platform)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkExecutables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_removeStaleLinks(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+	tmpDir.Write("target", nil)
+	if err := os.MkdirAll(p.BinPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"foo-a", "foo-b"} {
+		if err := createOrUpdateLink(p.BinPath(), tmpDir.Path("target"), name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := removeStaleLinks(p, []string{"foo-a", "foo-b"}, []string{"foo-b"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(PluginBinPath(p, "foo-a")); !os.IsNotExist(err) {
+		t.Errorf("expected link of foo-a to be removed, got err=%v", err)
+	}
+	if _, err := os.Lstat(PluginBinPath(p, "foo-b")); err != nil {
+		t.Errorf("expected link of foo-b to be kept, got err=%v", err)
+	}
+}
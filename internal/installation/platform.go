@@ -15,9 +15,12 @@
 package installation
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,19 +30,29 @@ import (
 	"sigs.k8s.io/krew/pkg/index"
 )
 
+var (
+	// muslLoaderGlob matches the dynamic loader of musl-based Linux systems.
+	muslLoaderGlob = "/lib/ld-musl-*.so.1"
+	// cpuInfoPath describes the processor of Linux systems.
+	cpuInfoPath = "/proc/cpuinfo"
+)
+
 // GetMatchingPlatform finds the platform spec in the specified plugin that
 // matches the os/arch of the current machine (can be overridden via KREW_OS
-// and/or KREW_ARCH).
+// and/or KREW_ARCH, as well as KREW_LIBC and KREW_ARM).
 func GetMatchingPlatform(platforms []index.Platform) (index.Platform, bool, error) {
 	return matchPlatform(platforms, OSArch())
 }
 
+// GetMatchingPlatformFor finds the platform spec in the specified plugin that
+// matches the given os/arch rather than the one of the current machine.
+func GetMatchingPlatformFor(platforms []index.Platform, env OSArchPair) (index.Platform, bool, error) {
+	return matchPlatform(platforms, env)
+}
+
 // matchPlatform returns the first matching platform to given os/arch.
 func matchPlatform(platforms []index.Platform, env OSArchPair) (index.Platform, bool, error) {
-	envLabels := labels.Set{
-		"os":   env.OS,
-		"arch": env.Arch,
-	}
+	envLabels := env.Labels()
 	klog.V(2).Infof("Matching platform for labels(%v)", envLabels)
 
 	for i, platform := range platforms {
@@ -58,20 +71,145 @@ func matchPlatform(platforms []index.Platform, env OSArchPair) (index.Platform,
 // OSArchPair is wrapper around operating system and architecture
 type OSArchPair struct {
 	OS, Arch string
+
+	// Libc is the C library of Linux systems, "glibc" or "musl".
+	Libc string
+	// ArmVariant is the instruction set version of 32-bit ARM systems, such
+	// as "v6" or "v7".
+	ArmVariant string
 }
 
-// String converts environment into a string
+// String converts environment into a string in the format of ParseOSArch.
 func (p OSArchPair) String() string {
-	return fmt.Sprintf("%s/%s", p.OS, p.Arch)
+	s := fmt.Sprintf("%s/%s", p.OS, p.Arch)
+	if p.ArmVariant != "" {
+		s += "/" + p.ArmVariant
+	}
+	if p.Libc != "" {
+		s += "/" + p.Libc
+	}
+	return s
+}
+
+// Compatible tells whether plugin archives selected for p can be installed on
+// o. The OS and the architecture must be equal, while the C library and the
+// ARM variant are only compared if both sides know them.
+func (p OSArchPair) Compatible(o OSArchPair) bool {
+	if p.OS != o.OS || p.Arch != o.Arch {
+		return false
+	}
+	if p.Libc != "" && o.Libc != "" && p.Libc != o.Libc {
+		return false
+	}
+	return p.ArmVariant == "" || o.ArmVariant == "" || p.ArmVariant == o.ArmVariant
+}
+
+// Labels returns the labels that platform selectors are matched against.
+// Labels that are not known for the environment are left out.
+func (p OSArchPair) Labels() labels.Set {
+	l := labels.Set{
+		"os":   p.OS,
+		"arch": p.Arch,
+	}
+	if p.Libc != "" {
+		l["libc"] = p.Libc
+	}
+	if p.ArmVariant != "" {
+		l["arm"] = p.ArmVariant
+	}
+	return l
+}
+
+// ParseOSArch parses an "os/arch" string, e.g. "linux/arm64". A variant can
+// be given for 32-bit ARM, e.g. "linux/arm/v7", followed by the C library of
+// Linux systems, e.g. "linux/amd64/musl" or "linux/arm/v7/glibc".
+func ParseOSArch(s string) (OSArchPair, error) {
+	invalid := errors.Errorf("invalid platform %q, must be in os/arch[/variant][/libc] format (e.g. linux/amd64 or linux/arm/v7/musl)", s)
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+		return OSArchPair{}, invalid
+	}
+	p := OSArchPair{OS: parts[0], Arch: parts[1]}
+	rest := parts[2:]
+	if len(rest) > 0 && p.Arch == "arm" && !isLibc(rest[0]) {
+		p.ArmVariant, rest = rest[0], rest[1:]
+		if p.ArmVariant == "" {
+			return OSArchPair{}, invalid
+		}
+	}
+	if len(rest) > 0 {
+		if !isLibc(rest[0]) {
+			return OSArchPair{}, invalid
+		}
+		p.Libc, rest = rest[0], rest[1:]
+	}
+	if len(rest) > 0 {
+		return OSArchPair{}, invalid
+	}
+	return p, nil
 }
 
+// isLibc tells whether s is one of the C libraries that Linux platforms are
+// selected by.
+func isLibc(s string) bool { return s == "glibc" || s == "musl" }
+
 // OSArch returns the OS/arch combination to be used on the current system. It
 // can be overridden by setting KREW_OS and/or KREW_ARCH environment variables.
+// The C library and the ARM variant are detected only if the OS and the
+// architecture are not overridden, and can be set with KREW_LIBC and KREW_ARM.
 func OSArch() OSArchPair {
-	return OSArchPair{
+	p := OSArchPair{
 		OS:   getEnvOrDefault("KREW_OS", runtime.GOOS),
 		Arch: getEnvOrDefault("KREW_ARCH", runtime.GOARCH),
 	}
+	native := p.OS == runtime.GOOS && p.Arch == runtime.GOARCH
+	if native && p.OS == "linux" {
+		p.Libc = detectLibc()
+	}
+	if native && p.OS == "linux" && p.Arch == "arm" {
+		p.ArmVariant = detectArmVariant()
+	}
+	p.Libc = getEnvOrDefault("KREW_LIBC", p.Libc)
+	p.ArmVariant = getEnvOrDefault("KREW_ARM", p.ArmVariant)
+	return p
+}
+
+// detectLibc returns the C library of the current Linux system.
+func detectLibc() string {
+	if m, _ := filepath.Glob(muslLoaderGlob); len(m) > 0 {
+		return "musl"
+	}
+	return "glibc"
+}
+
+// detectArmVariant returns the ARM variant of the current Linux system from
+// the processor information, or an empty string if it cannot be determined.
+func detectArmVariant() string {
+	f, err := os.Open(cpuInfoPath)
+	if err != nil {
+		klog.V(2).Infof("Cannot detect ARM variant: %v", err)
+		return ""
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		kv := strings.SplitN(s.Text(), ":", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) != "CPU architecture" {
+			continue
+		}
+		switch v := strings.TrimSpace(kv[1]); v {
+		case "6":
+			return "v6"
+		case "7", "8":
+			// 64-bit processors run 32-bit code built for ARMv7.
+			return "v7"
+		default:
+			klog.V(2).Infof("Unknown ARM architecture %q", v)
+			return ""
+		}
+	}
+	return ""
 }
 
 func getEnvOrDefault(env, absent string) string {
@@ -16,17 +16,28 @@ package installation
 
 import (
 	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/krew/internal/testutil"
 	"sigs.k8s.io/krew/pkg/index"
 )
 
 func Test_osArch(t *testing.T) {
+	defer func(orig string) { muslLoaderGlob = orig }(muslLoaderGlob)
+	muslLoaderGlob = filepath.Join(testutil.NewTempDir(t).Root(), "ld-musl-*.so.1")
+
 	in := OSArchPair{OS: runtime.GOOS, Arch: runtime.GOARCH}
+	if runtime.GOOS == "linux" {
+		in.Libc = "glibc"
+	}
+	if runtime.GOOS == "linux" && runtime.GOARCH == "arm" {
+		in.ArmVariant = detectArmVariant()
+	}
 
 	if diff := cmp.Diff(in, OSArch()); diff != "" {
 		t.Errorf("os/arch got a different result:\n%s", diff)
@@ -47,6 +58,98 @@ func Test_osArch_override(t *testing.T) {
 	}
 }
 
+func Test_osArch_labelOverride(t *testing.T) {
+	os.Setenv("KREW_OS", "linux")
+	os.Setenv("KREW_ARCH", "arm")
+	os.Setenv("KREW_LIBC", "musl")
+	os.Setenv("KREW_ARM", "v6")
+	defer func() {
+		os.Unsetenv("KREW_OS")
+		os.Unsetenv("KREW_ARCH")
+		os.Unsetenv("KREW_LIBC")
+		os.Unsetenv("KREW_ARM")
+	}()
+
+	want := OSArchPair{OS: "linux", Arch: "arm", Libc: "musl", ArmVariant: "v6"}
+	if diff := cmp.Diff(want, OSArch()); diff != "" {
+		t.Errorf("label override got a different result:\n%s", diff)
+	}
+}
+
+func Test_detectLibc(t *testing.T) {
+	defer func(orig string) { muslLoaderGlob = orig }(muslLoaderGlob)
+	tmpDir := testutil.NewTempDir(t)
+	muslLoaderGlob = tmpDir.Path("ld-musl-*.so.1")
+
+	if got := detectLibc(); got != "glibc" {
+		t.Errorf("detectLibc() without musl loader = %q, want glibc", got)
+	}
+	tmpDir.Write("ld-musl-armhf.so.1", nil)
+	if got := detectLibc(); got != "musl" {
+		t.Errorf("detectLibc() with musl loader = %q, want musl", got)
+	}
+}
+
+func Test_detectArmVariant(t *testing.T) {
+	defer func(orig string) { cpuInfoPath = orig }(cpuInfoPath)
+	tmpDir := testutil.NewTempDir(t)
+
+	tests := []struct {
+		cpuinfo string
+		want    string
+	}{
+		{cpuinfo: "processor\t: 0\nCPU architecture: 6\n", want: "v6"},
+		{cpuinfo: "processor\t: 0\nCPU architecture: 7\n", want: "v7"},
+		{cpuinfo: "CPU architecture: 8\n", want: "v7"},
+		{cpuinfo: "CPU architecture: 5TEJ\n", want: ""},
+		{cpuinfo: "processor\t: 0\n", want: ""},
+	}
+	for _, tt := range tests {
+		cpuInfoPath = tmpDir.Write("cpuinfo", []byte(tt.cpuinfo)).Path("cpuinfo")
+		if got := detectArmVariant(); got != tt.want {
+			t.Errorf("detectArmVariant() for %q = %q, want %q", tt.cpuinfo, got, tt.want)
+		}
+	}
+
+	cpuInfoPath = tmpDir.Path("missing")
+	if got := detectArmVariant(); got != "" {
+		t.Errorf("detectArmVariant() without cpuinfo = %q, want empty", got)
+	}
+}
+
+func Test_matchPlatform_extendedLabels(t *testing.T) {
+	musl := testutil.NewPlatform().WithSelector(&metav1.LabelSelector{
+		MatchLabels: map[string]string{"os": "linux", "libc": "musl"},
+	}).V()
+	armv6 := testutil.NewPlatform().WithSelector(&metav1.LabelSelector{
+		MatchLabels: map[string]string{"os": "linux", "arch": "arm", "arm": "v6"},
+	}).V()
+	linux := testutil.NewPlatform().WithOSArch("linux", "arm").V()
+	platforms := []index.Platform{musl, armv6, linux}
+
+	tests := []struct {
+		env  OSArchPair
+		want index.Platform
+	}{
+		{env: OSArchPair{OS: "linux", Arch: "amd64", Libc: "musl"}, want: musl},
+		{env: OSArchPair{OS: "linux", Arch: "arm", Libc: "glibc", ArmVariant: "v6"}, want: armv6},
+		{env: OSArchPair{OS: "linux", Arch: "arm", Libc: "glibc", ArmVariant: "v7"}, want: linux},
+		{env: OSArchPair{OS: "linux", Arch: "arm"}, want: linux},
+	}
+	for _, tt := range tests {
+		got, ok, err := matchPlatform(platforms, tt.env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("no platform matched %+v", tt.env)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("matchPlatform(%+v) got a different platform:\n%s", tt.env, diff)
+		}
+	}
+}
+
 func Test_matchPlatform(t *testing.T) {
 	target := OSArchPair{OS: "foo", Arch: "amd64"}
 	matchingPlatform := testutil.NewPlatform().WithOSArch(target.OS, target.Arch).V()
@@ -72,3 +175,66 @@ func Test_matchPlatform(t *testing.T) {
 		t.Fatal("got a matching platform, but was not expecting")
 	}
 }
+
+func TestParseOSArch(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    OSArchPair
+		wantErr bool
+	}{
+		{in: "linux/arm64", want: OSArchPair{OS: "linux", Arch: "arm64"}},
+		{in: "windows/amd64", want: OSArchPair{OS: "windows", Arch: "amd64"}},
+		{in: "linux", wantErr: true},
+		{in: "linux/", wantErr: true},
+		{in: "/amd64", wantErr: true},
+		{in: "linux/arm/v7", want: OSArchPair{OS: "linux", Arch: "arm", ArmVariant: "v7"}},
+		{in: "linux/amd64/v2", wantErr: true},
+		{in: "linux/arm/", wantErr: true},
+		{in: "linux/amd64/musl", want: OSArchPair{OS: "linux", Arch: "amd64", Libc: "musl"}},
+		{in: "linux/arm/v7/glibc", want: OSArchPair{OS: "linux", Arch: "arm", ArmVariant: "v7", Libc: "glibc"}},
+		{in: "linux/arm/musl", want: OSArchPair{OS: "linux", Arch: "arm", Libc: "musl"}},
+		{in: "linux/amd64/uclibc", wantErr: true},
+		{in: "linux/amd64/musl/v7", wantErr: true},
+		{in: "linux/arm/v7/musl/extra", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseOSArch(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOSArch(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseOSArch(%q) mismatch:\n%s", tt.in, diff)
+			}
+			if !tt.wantErr && got.String() != tt.in {
+				t.Errorf("String() = %q, want %q", got.String(), tt.in)
+			}
+		})
+	}
+}
+
+func TestOSArchPair_Compatible(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b OSArchPair
+		want bool
+	}{
+		{name: "equal", a: OSArchPair{OS: "linux", Arch: "amd64"}, b: OSArchPair{OS: "linux", Arch: "amd64"}, want: true},
+		{name: "different os", a: OSArchPair{OS: "linux", Arch: "amd64"}, b: OSArchPair{OS: "darwin", Arch: "amd64"}},
+		{name: "different arch", a: OSArchPair{OS: "linux", Arch: "amd64"}, b: OSArchPair{OS: "linux", Arch: "arm64"}},
+		{name: "libc on one side", a: OSArchPair{OS: "linux", Arch: "amd64"}, b: OSArchPair{OS: "linux", Arch: "amd64", Libc: "musl"}, want: true},
+		{name: "different libc", a: OSArchPair{OS: "linux", Arch: "amd64", Libc: "glibc"}, b: OSArchPair{OS: "linux", Arch: "amd64", Libc: "musl"}},
+		{name: "variant on one side", a: OSArchPair{OS: "linux", Arch: "arm", ArmVariant: "v7"}, b: OSArchPair{OS: "linux", Arch: "arm"}, want: true},
+		{name: "different variant", a: OSArchPair{OS: "linux", Arch: "arm", ArmVariant: "v6"}, b: OSArchPair{OS: "linux", Arch: "arm", ArmVariant: "v7"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Compatible(tt.b); got != tt.want {
+				t.Errorf("%v.Compatible(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := tt.b.Compatible(tt.a); got != tt.want {
+				t.Errorf("%v.Compatible(%v) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
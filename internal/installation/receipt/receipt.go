@@ -19,16 +19,16 @@ import (
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/yaml"
 
 	"sigs.k8s.io/krew/internal/index/indexscanner"
 	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/scheme"
 )
 
 // Store saves the given receipt at the destination.
 // The caller has to ensure that the destination directory exists.
 func Store(receipt index.Receipt, dest string) error {
-	yamlBytes, err := yaml.Marshal(receipt)
+	yamlBytes, err := scheme.EncodeReceipt(receipt)
 	if err != nil {
 		return errors.Wrapf(err, "convert to yaml")
 	}
@@ -65,6 +65,26 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestStore_keepsAPIVersion(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+
+	testPlugin := testutil.NewPlugin().WithName("foo").
+		WithTypeMeta(metav1.TypeMeta{APIVersion: "krew.googlecontainertools.github.com/v1beta1", Kind: constants.PluginKind}).
+		WithDeprecated("use bar instead").V()
+	testReceipt := testutil.NewReceipt().WithPlugin(testPlugin).V()
+	if err := Store(testReceipt, tmpDir.Path("foo.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(tmpDir.Path("foo.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(testReceipt, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
 func TestLoad_preservesNonExistsError(t *testing.T) {
 	_, err := Load("non-existing.yaml")
 	if !os.IsNotExist(err) {
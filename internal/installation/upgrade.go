@@ -64,6 +64,12 @@ func Upgrade(p environment.Paths, plugin index.Plugin, indexName string) error {
 		return ErrIsAlreadyUpgraded
 	}
 	klog.V(1).Infof("Plugin needs upgrade (%s < %s)", curv, newv)
+	if err := checkPolicy(p, plugin, indexName, candidate, false); err != nil {
+		return err
+	}
+	if err := checkExecutables(p, plugin.Name, candidate); err != nil {
+		return err
+	}
 
 	// Re-Install
 	klog.V(1).Infof("Installing new version %s", newVersion)
@@ -78,8 +84,8 @@ func Upgrade(p environment.Paths, plugin index.Plugin, indexName string) error {
 	}
 
 	klog.V(2).Infof("Upgrading install receipt for plugin %s", plugin.Name)
-	if err = receipt.Store(receipt.New(plugin, indexName, installReceipt.CreationTimestamp), p.PluginInstallReceiptPath(plugin.Name)); err != nil {
-		return errors.Wrap(err, "installation receipt could not be stored, uninstall may fail")
+	if err := storeReceipt(p, plugin, indexName, installReceipt, candidate); err != nil {
+		return err
 	}
 
 	// Clean old installations
@@ -87,6 +93,79 @@ func Upgrade(p environment.Paths, plugin index.Plugin, indexName string) error {
 	return cleanupInstallation(p, plugin, curVersion)
 }
 
+// Migrate changes the index that an installed plugin is installed from to
+// indexName, from which plugin was loaded. The plugin is reinstalled from
+// that index unless the installed version and archive are the same, even if
+// it is a downgrade.
+func Migrate(p environment.Paths, plugin index.Plugin, indexName string) error {
+	installReceipt, err := receipt.Load(p.PluginInstallReceiptPath(plugin.Name))
+	if os.IsNotExist(err) {
+		return ErrIsNotInstalled
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to load install receipt for plugin %q", plugin.Name)
+	}
+	source := installReceipt.Status.Source.Name
+	if source == "" {
+		source = constants.DefaultIndexName
+	}
+	if source == indexName {
+		return errors.Errorf("plugin %q is already installed from index %q", plugin.Name, indexName)
+	}
+
+	candidate, ok, err := GetMatchingPlatform(plugin.Spec.Platforms)
+	if err != nil {
+		return errors.Wrap(err, "failed trying to find a matching platform in plugin spec")
+	}
+	if !ok {
+		return errors.Errorf("plugin %q does not offer installation for this platform (%s)",
+			plugin.Name, OSArch())
+	}
+	if err := checkPolicy(p, plugin, indexName, candidate, false); err != nil {
+		return err
+	}
+	if err := checkExecutables(p, plugin.Name, candidate); err != nil {
+		return err
+	}
+
+	curVersion := installReceipt.Spec.Version
+	installed, ok, err := GetMatchingPlatform(installReceipt.Spec.Platforms)
+	if err == nil && ok && curVersion == plugin.Spec.Version && installed.Sha256 == candidate.Sha256 {
+		klog.V(1).Infof("Index %q has the installed version %s of plugin %q, only updating the receipt", indexName, curVersion, plugin.Name)
+	} else {
+		klog.V(1).Infof("Reinstalling plugin %q at version %s from index %q", plugin.Name, plugin.Spec.Version, indexName)
+		if err := install(installOperation{
+			pluginName: plugin.Name,
+			platform:   candidate,
+
+			installDir: p.PluginVersionInstallPath(plugin.Name, plugin.Spec.Version),
+			binDir:     p.BinPath(),
+		}, InstallOpts{}); err != nil {
+			return errors.Wrap(err, "failed to install the plugin from the new index")
+		}
+	}
+
+	klog.V(2).Infof("Updating install receipt for plugin %s", plugin.Name)
+	if err := storeReceipt(p, plugin, indexName, installReceipt, candidate); err != nil {
+		return err
+	}
+	if curVersion == plugin.Spec.Version {
+		return nil
+	}
+	return cleanupInstallation(p, plugin, curVersion)
+}
+
+// storeReceipt replaces the receipt of a plugin after it was reinstalled from
+// the given platform, and removes the links of executables that the platform
+// does not have anymore.
+func storeReceipt(p environment.Paths, plugin index.Plugin, indexName string, old index.Receipt, platform index.Platform) error {
+	r := receipt.New(plugin, indexName, old.CreationTimestamp)
+	r.Status.Executables = executableNames(platform)
+	if err := receipt.Store(r, p.PluginInstallReceiptPath(plugin.Name)); err != nil {
+		return errors.Wrap(err, "installation receipt could not be stored, uninstall may fail")
+	}
+	return removeStaleLinks(p, old.Status.Executables, r.Status.Executables)
+}
+
 // cleanupInstallation will remove a plugin directly if it not krew.
 //
 // Krew on Windows needs special care because active directories can't be
@@ -0,0 +1,54 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/krew/internal/environment"
+	"sigs.k8s.io/krew/internal/installation/receipt"
+	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/index"
+)
+
+func TestMigrate(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	p := environment.NewPaths(tmpDir.Root())
+	plugin := testutil.NewPlugin().WithName("foo").
+		WithPlatforms(testutil.NewPlatform().WithOSArch(runtime.GOOS, runtime.GOARCH).V()).V()
+
+	if err := Migrate(p, plugin, "other"); err != ErrIsNotInstalled {
+		t.Fatalf("expected ErrIsNotInstalled, got: %v", err)
+	}
+
+	tmpDir.WriteYAML("receipts/foo.yaml", testutil.NewReceipt().WithPlugin(plugin).V())
+	if err := Migrate(p, plugin, "default"); err == nil || !strings.Contains(err.Error(), "already installed from") {
+		t.Errorf("expected error migrating to the current index, got: %v", err)
+	}
+
+	// The archive is the same, so the plugin is not downloaded again.
+	if err := Migrate(p, plugin, "other"); err != nil {
+		t.Fatal(err)
+	}
+	r, err := receipt.Load(p.PluginInstallReceiptPath("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status.Source != (index.SourceIndex{Name: "other"}) {
+		t.Errorf("expected receipt to have source index %q, got %+v", "other", r.Status.Source)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package network controls whether krew may access the network.
+package network
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// OfflineEnv enables offline mode when set to a true value, such as "1" or
+// "true".
+const OfflineEnv = "KREW_OFFLINE"
+
+var offline bool
+
+// SetOffline enables or disables offline mode for the current process.
+func SetOffline(v bool) { offline = v }
+
+// Offline tells whether offline mode is enabled with SetOffline or the
+// KREW_OFFLINE environment variable.
+func Offline() bool {
+	if offline {
+		return true
+	}
+	v := os.Getenv(OfflineEnv)
+	if v == "" {
+		return false
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil {
+		klog.Warningf("Ignoring invalid value %q of %s, must be a boolean such as \"1\" or \"false\"", v, OfflineEnv)
+		return false
+	}
+	return on
+}
+
+// Check returns an error describing that op needs network access if offline
+// mode is enabled, so that operations fail immediately instead of timing out.
+func Check(op string) error {
+	if !Offline() {
+		return nil
+	}
+	return errors.Errorf("%s needs network access, but offline mode is enabled (--offline or %s)", op, OfflineEnv)
+}
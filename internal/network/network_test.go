@@ -0,0 +1,65 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	defer SetOffline(false)
+	os.Unsetenv(OfflineEnv)
+
+	if err := Check("test"); err != nil {
+		t.Errorf("expected no error when online, got: %v", err)
+	}
+
+	SetOffline(true)
+	if err := Check("test"); err == nil {
+		t.Error("expected error with SetOffline(true)")
+	}
+	SetOffline(false)
+
+	os.Setenv(OfflineEnv, "1")
+	defer os.Unsetenv(OfflineEnv)
+	if !Offline() {
+		t.Errorf("expected %s to enable offline mode", OfflineEnv)
+	}
+	if err := Check("test"); err == nil {
+		t.Errorf("expected error with %s set", OfflineEnv)
+	}
+}
+
+func TestOffline_env(t *testing.T) {
+	defer os.Unsetenv(OfflineEnv)
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "", want: false},
+		{value: "1", want: true},
+		{value: "true", want: true},
+		{value: "0", want: false},
+		{value: "false", want: false},
+		{value: "foo", want: false},
+	}
+	for _, tt := range tests {
+		os.Setenv(OfflineEnv, tt.value)
+		if got := Offline(); got != tt.want {
+			t.Errorf("Offline() with %s=%q = %v, want %v", OfflineEnv, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy restricts which indexes can be added and which plugins can
+// be installed. The policy is read from a system-wide file, and the file in
+// the KREW_POLICY environment variable can only restrict it further.
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// APIVersion is the version of the policy file format.
+	APIVersion = "policy.krew.sigs.k8s.io/v1alpha1"
+	// Kind is the kind of the policy file.
+	Kind = "Policy"
+
+	// Env is the environment variable that overrides the path of the policy
+	// file.
+	Env = "KREW_POLICY"
+)
+
+// Policy holds the rules that indexes and plugins must follow. The zero value
+// allows everything.
+type Policy struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// AllowedIndexURLs are patterns of the URLs that indexes can be added
+	// from. A * matches any sequence of characters. If it is empty, indexes
+	// can be added from any URL.
+	AllowedIndexURLs []string `json:"allowedIndexURLs,omitempty"`
+	// DeniedPlugins are patterns of plugins that cannot be installed, as NAME
+	// or INDEX/NAME. A * matches any sequence of characters.
+	DeniedPlugins []string `json:"deniedPlugins,omitempty"`
+	// RequireSignedIndexes only allows adding indexes with signing keys, and
+	// installing plugins from such indexes.
+	RequireSignedIndexes bool `json:"requireSignedIndexes,omitempty"`
+	// AllowedDigests maps plugin names to the sha256 sums of the archives
+	// that can be installed for them. Plugins that are not listed can be
+	// installed from any archive.
+	AllowedDigests map[string][]string `json:"allowedDigests,omitempty"`
+
+	// requiredIndexURLs are patterns of a merged policy that index URLs
+	// must match in addition to AllowedIndexURLs.
+	requiredIndexURLs []string
+}
+
+// ViolationError is returned when an operation is not allowed by the policy.
+type ViolationError struct {
+	Reason string
+}
+
+func (e *ViolationError) Error() string {
+	return "policy violation: " + e.Reason
+}
+
+// IsViolation tells whether err was caused by a policy violation.
+func IsViolation(err error) bool {
+	_, ok := errors.Cause(err).(*ViolationError)
+	return ok
+}
+
+func violation(format string, args ...interface{}) error {
+	return &ViolationError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// systemPath is the path of the system-wide policy file.
+var systemPath = defaultSystemPath()
+
+func defaultSystemPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "krew", "policy.yaml")
+	}
+	return "/etc/krew/policy.yaml"
+}
+
+// SystemPath returns the path of the system-wide policy file.
+func SystemPath() string { return systemPath }
+
+// Load reads the system-wide policy file and merges the policy file in
+// KREW_POLICY into it, so that an operation must be allowed by both. A missing
+// system-wide file allows everything, while it is an error if the file in
+// KREW_POLICY does not exist.
+func Load() (Policy, error) {
+	p, err := LoadFile(SystemPath())
+	if os.IsNotExist(errors.Cause(err)) {
+		p, err = Policy{}, nil
+	}
+	if err != nil {
+		return Policy{}, err
+	}
+	if path, ok := os.LookupEnv(Env); ok {
+		user, err := LoadFile(path)
+		if err != nil {
+			return Policy{}, err
+		}
+		p = Merge(p, user)
+	}
+	return p, nil
+}
+
+// Merge returns a policy that allows only what both p and o allow: the denied
+// plugins of both, the index URLs allowed by both, the digests allowed by both
+// for the same plugin, and signed indexes if either requires them.
+func Merge(p, o Policy) Policy {
+	out := Policy{
+		APIVersion:           APIVersion,
+		Kind:                 Kind,
+		DeniedPlugins:        append(append([]string{}, p.DeniedPlugins...), o.DeniedPlugins...),
+		RequireSignedIndexes: p.RequireSignedIndexes || o.RequireSignedIndexes,
+	}
+
+	out.AllowedIndexURLs, out.requiredIndexURLs = p.AllowedIndexURLs, p.requiredIndexURLs
+	for _, urls := range [][]string{o.AllowedIndexURLs, o.requiredIndexURLs} {
+		if len(urls) == 0 {
+			continue
+		}
+		if len(out.AllowedIndexURLs) == 0 {
+			out.AllowedIndexURLs = urls
+		} else {
+			out.requiredIndexURLs = append(out.requiredIndexURLs, urls...)
+		}
+	}
+
+	if len(p.AllowedDigests)+len(o.AllowedDigests) > 0 {
+		out.AllowedDigests = make(map[string][]string)
+	}
+	for name, digests := range p.AllowedDigests {
+		out.AllowedDigests[name] = digests
+	}
+	for name, digests := range o.AllowedDigests {
+		prev, ok := out.AllowedDigests[name]
+		if !ok {
+			out.AllowedDigests[name] = digests
+			continue
+		}
+		both := []string{}
+		for _, d := range digests {
+			if containsFold(prev, d) {
+				both = append(both, d)
+			}
+		}
+		out.AllowedDigests[name] = both
+	}
+	return out
+}
+
+// LoadFile reads the policy file at path. If the file does not exist, it
+// returns an error that can be tested with os.IsNotExist.
+func LoadFile(path string) (Policy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Policy{}, err
+	}
+	var p Policy
+	if err := yaml.Unmarshal(b, &p); err != nil {
+		return Policy{}, errors.Wrapf(err, "failed to parse policy %q", path)
+	}
+	if p.APIVersion != APIVersion || p.Kind != Kind {
+		return Policy{}, errors.Errorf("unsupported policy %q: apiVersion=%q kind=%q", path, p.APIVersion, p.Kind)
+	}
+	klog.V(2).Infof("Loaded policy from %q", path)
+	return p, nil
+}
+
+// CheckIndex checks whether an index can be added from url. signed tells
+// whether the index is added with signing keys.
+func (p Policy) CheckIndex(url string, signed bool) error {
+	if len(p.AllowedIndexURLs) > 0 && !matchAny(p.AllowedIndexURLs, url) {
+		return violation("index URL %q is not allowed", url)
+	}
+	if len(p.requiredIndexURLs) > 0 && !matchAny(p.requiredIndexURLs, url) {
+		return violation("index URL %q is not allowed", url)
+	}
+	if p.RequireSignedIndexes && !signed {
+		return violation("index %q must be added with signing keys", url)
+	}
+	return nil
+}
+
+// CheckPlugin checks whether a plugin can be installed from an index with the
+// archive that has the given sha256 sum. signed tells whether the index has
+// signing keys.
+func (p Policy) CheckPlugin(indexName, pluginName string, signed bool, sha256sum string) error {
+	if matchAny(p.DeniedPlugins, pluginName) || matchAny(p.DeniedPlugins, indexName+"/"+pluginName) {
+		return violation("plugin %q is denied", indexName+"/"+pluginName)
+	}
+	if p.RequireSignedIndexes && !signed {
+		return violation("plugin %q must be installed from an index with signing keys", indexName+"/"+pluginName)
+	}
+	if digests, ok := p.AllowedDigests[pluginName]; ok && !containsFold(digests, sha256sum) {
+		return violation("sha256 %s of plugin %q is not allowed", sha256sum, pluginName)
+	}
+	return nil
+}
+
+// containsFold tells whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny tells whether s matches one of the patterns, in which a * matches
+// any sequence of characters.
+func matchAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		parts := strings.Split(pattern, "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		if regexp.MustCompile("^" + strings.Join(parts, ".*") + "$").MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"os"
+	"testing"
+
+	"sigs.k8s.io/krew/internal/testutil"
+)
+
+func TestLoad(t *testing.T) {
+	tmpDir := testutil.NewTempDir(t)
+	tmpDir.Write("policy.yaml", []byte(`apiVersion: policy.krew.sigs.k8s.io/v1alpha1
+kind: Policy
+deniedPlugins:
+- foo
+`))
+	tmpDir.Write("unsupported.yaml", []byte("apiVersion: v1\nkind: Policy\n"))
+	tmpDir.Write("system.yaml", []byte(`apiVersion: policy.krew.sigs.k8s.io/v1alpha1
+kind: Policy
+deniedPlugins:
+- bar
+requireSignedIndexes: true
+`))
+
+	defer func(orig string) { systemPath = orig }(systemPath)
+	systemPath = tmpDir.Path("missing-system.yaml")
+
+	os.Setenv(Env, tmpDir.Path("policy.yaml"))
+	defer os.Unsetenv(Env)
+	p, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.DeniedPlugins) != 1 || p.DeniedPlugins[0] != "foo" {
+		t.Errorf("DeniedPlugins = %v, want [foo]", p.DeniedPlugins)
+	}
+
+	os.Setenv(Env, tmpDir.Path("missing.yaml"))
+	if _, err := Load(); err == nil {
+		t.Error("expected error for missing policy in " + Env)
+	}
+	os.Setenv(Env, tmpDir.Path("unsupported.yaml"))
+	if _, err := Load(); err == nil {
+		t.Error("expected error for unsupported policy version")
+	}
+
+	systemPath = tmpDir.Path("system.yaml")
+	os.Setenv(Env, tmpDir.Path("policy.yaml"))
+	p, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.CheckPlugin("default", "bar", true, ""); err == nil {
+		t.Error("plugin denied by the system policy should stay denied with " + Env)
+	}
+	if err := p.CheckPlugin("default", "foo", true, ""); err == nil {
+		t.Error("plugin denied by the policy in " + Env + " should be denied")
+	}
+	if !p.RequireSignedIndexes {
+		t.Error("requireSignedIndexes of the system policy should be kept")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	system := Policy{
+		AllowedIndexURLs: []string{"https://git.example.com/*"},
+		AllowedDigests:   map[string][]string{"foo": {"AA", "bb"}},
+	}
+	user := Policy{
+		AllowedIndexURLs: []string{"https://git.example.com/team/*", "https://github.com/*"},
+		AllowedDigests:   map[string][]string{"foo": {"aa", "cc"}, "bar": {"dd"}},
+	}
+	p := Merge(system, user)
+
+	indexTests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://git.example.com/team/index.git"},
+		{url: "https://git.example.com/other/index.git", wantErr: true},
+		{url: "https://github.com/evil/krew-index.git", wantErr: true},
+	}
+	for _, tt := range indexTests {
+		if err := p.CheckIndex(tt.url, false); (err != nil) != tt.wantErr {
+			t.Errorf("CheckIndex(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+	}
+
+	pluginTests := []struct {
+		name    string
+		sha256  string
+		wantErr bool
+	}{
+		{name: "foo", sha256: "aa"},
+		{name: "foo", sha256: "bb", wantErr: true},
+		{name: "foo", sha256: "cc", wantErr: true},
+		{name: "bar", sha256: "dd"},
+		{name: "bar", sha256: "ee", wantErr: true},
+		{name: "baz", sha256: "ff"},
+	}
+	for _, tt := range pluginTests {
+		if err := p.CheckPlugin("default", tt.name, false, tt.sha256); (err != nil) != tt.wantErr {
+			t.Errorf("CheckPlugin(%q, %q) error = %v, wantErr %v", tt.name, tt.sha256, err, tt.wantErr)
+		}
+	}
+
+	if err := Merge(Policy{}, user).CheckIndex("https://github.com/foo/index.git", false); err != nil {
+		t.Errorf("merging into an empty policy should keep the allowed URLs, got %v", err)
+	}
+}
+
+func TestCheckIndex(t *testing.T) {
+	p := Policy{AllowedIndexURLs: []string{"https://github.com/kubernetes-sigs/krew-index.git", "https://git.example.com/*"}}
+	tests := []struct {
+		url     string
+		signed  bool
+		require bool
+		wantErr bool
+	}{
+		{url: "https://github.com/kubernetes-sigs/krew-index.git"},
+		{url: "https://git.example.com/team/index.git"},
+		{url: "https://github.com/evil/krew-index.git", wantErr: true},
+		{url: "https://git.example.com.evil.com/index.git", wantErr: true},
+		{url: "https://git.example.com/team/index.git", require: true, wantErr: true},
+		{url: "https://git.example.com/team/index.git", require: true, signed: true},
+	}
+	for _, tt := range tests {
+		p.RequireSignedIndexes = tt.require
+		err := p.CheckIndex(tt.url, tt.signed)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckIndex(%q, signed=%v, require=%v) error = %v, wantErr %v", tt.url, tt.signed, tt.require, err, tt.wantErr)
+		}
+		if err != nil && !IsViolation(err) {
+			t.Errorf("expected a policy violation, got %v", err)
+		}
+	}
+	if err := (Policy{}).CheckIndex("https://example.com/anything.git", false); err != nil {
+		t.Errorf("empty policy should allow any index, got %v", err)
+	}
+}
+
+func TestCheckPlugin(t *testing.T) {
+	p := Policy{
+		DeniedPlugins:  []string{"sniff", "corp/*"},
+		AllowedDigests: map[string][]string{"ctx": {"ABCDEF"}},
+	}
+	tests := []struct {
+		index, plugin string
+		signed        bool
+		require       bool
+		sha256        string
+		wantErr       bool
+	}{
+		{index: "default", plugin: "foo"},
+		{index: "default", plugin: "sniff", wantErr: true},
+		{index: "other", plugin: "sniff", wantErr: true},
+		{index: "corp", plugin: "foo", wantErr: true},
+		{index: "default", plugin: "ctx", sha256: "abcdef"},
+		{index: "default", plugin: "ctx", sha256: "123456", wantErr: true},
+		{index: "default", plugin: "foo", require: true, wantErr: true},
+		{index: "default", plugin: "foo", require: true, signed: true},
+	}
+	for _, tt := range tests {
+		p.RequireSignedIndexes = tt.require
+		err := p.CheckPlugin(tt.index, tt.plugin, tt.signed, tt.sha256)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckPlugin(%s/%s) error = %v, wantErr %v", tt.index, tt.plugin, err, tt.wantErr)
+		}
+	}
+}
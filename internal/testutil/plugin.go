@@ -62,6 +62,8 @@ func (p *P) WithShortDescription(v string) *P     { p.v.Spec.ShortDescription =
 func (p *P) WithTypeMeta(v metav1.TypeMeta) *P    { p.v.TypeMeta = v; return p }
 func (p *P) WithPlatforms(v ...index.Platform) *P { p.v.Spec.Platforms = v; return p }
 func (p *P) WithVersion(v string) *P              { p.v.Spec.Version = v; return p }
+func (p *P) WithDeprecated(v string) *P           { p.v.Spec.Deprecated = v; return p }
+func (p *P) WithReplacedBy(v string) *P           { p.v.Spec.ReplacedBy = v; return p }
 func (p *P) V() index.Plugin                      { return p.v }
 
 func NewPlatform() *R {
@@ -96,9 +98,11 @@ func (p *R) WithOSArch(os, arch string) *R {
 	return p
 }
 
-func (p *R) WithSelector(v *metav1.LabelSelector) *R { p.v.Selector = v; return p }
-func (p *R) WithFiles(v []index.FileOperation) *R    { p.v.Files = v; return p }
-func (p *R) WithBin(v string) *R                     { p.v.Bin = v; return p }
-func (p *R) WithURI(v string) *R                     { p.v.URI = v; return p }
-func (p *R) WithSHA256(v string) *R                  { p.v.Sha256 = v; return p }
-func (p *R) V() index.Platform                       { return p.v }
+func (p *R) WithSelector(v *metav1.LabelSelector) *R  { p.v.Selector = v; return p }
+func (p *R) WithFiles(v []index.FileOperation) *R     { p.v.Files = v; return p }
+func (p *R) WithBin(v string) *R                      { p.v.Bin = v; return p }
+func (p *R) WithURI(v string) *R                      { p.v.URI = v; return p }
+func (p *R) WithSHA256(v string) *R                   { p.v.Sha256 = v; return p }
+func (p *R) WithExecutables(v ...index.Executable) *R { p.v.Executables = v; return p }
+func (p *R) WithCompletion(v string) *R               { p.v.Completion = v; return p }
+func (p *R) V() index.Platform                        { return p.v }
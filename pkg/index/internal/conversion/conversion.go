@@ -0,0 +1,35 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conversion copies plugin manifests and receipts between their
+// versioned schemas and the internal representation of package index.
+package conversion
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Convert copies in to out, which are different versions of the same kind of
+// object. Fields are matched by their JSON names: the fields of in that out
+// does not have are dropped, and the fields of out that in does not have are
+// left empty.
+func Convert(in, out interface{}) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert")
+	}
+	return errors.Wrap(json.Unmarshal(b, out), "failed to convert")
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scheme decodes plugin manifests and receipts of every supported
+// apiVersion into the internal representation of package index, and encodes
+// them back into the apiVersion they were read from.
+package scheme
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/v1alpha2"
+	"sigs.k8s.io/krew/pkg/index/v1beta1"
+)
+
+// SupportedAPIVersions are the apiVersions of the manifests and receipts that
+// this version of krew can read.
+var SupportedAPIVersions = []string{v1alpha2.APIVersion, v1beta1.APIVersion}
+
+// IsSupportedAPIVersion tells whether manifests of apiVersion can be read.
+func IsSupportedAPIVersion(apiVersion string) bool {
+	for _, v := range SupportedAPIVersions {
+		if v == apiVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodePlugin decodes a plugin manifest of any supported apiVersion. A
+// manifest of an unsupported apiVersion is decoded as is, so that validation
+// can reject it with its apiVersion.
+func DecodePlugin(b []byte) (index.Plugin, error) {
+	apiVersion, err := decodeAPIVersion(b)
+	if err != nil {
+		return index.Plugin{}, err
+	}
+	switch apiVersion {
+	case v1alpha2.APIVersion:
+		var p v1alpha2.Plugin
+		if err := unmarshal(b, &p); err != nil {
+			return index.Plugin{}, err
+		}
+		return p.ToHub()
+	case v1beta1.APIVersion:
+		var p v1beta1.Plugin
+		if err := unmarshal(b, &p); err != nil {
+			return index.Plugin{}, err
+		}
+		return p.ToHub()
+	default:
+		var p index.Plugin
+		err := unmarshal(b, &p)
+		return p, err
+	}
+}
+
+// DecodeReceipt decodes a plugin receipt of any supported apiVersion.
+func DecodeReceipt(b []byte) (index.Receipt, error) {
+	apiVersion, err := decodeAPIVersion(b)
+	if err != nil {
+		return index.Receipt{}, err
+	}
+	switch apiVersion {
+	case v1alpha2.APIVersion:
+		var r v1alpha2.Receipt
+		if err := unmarshal(b, &r); err != nil {
+			return index.Receipt{}, err
+		}
+		return r.ToHub()
+	case v1beta1.APIVersion:
+		var r v1beta1.Receipt
+		if err := unmarshal(b, &r); err != nil {
+			return index.Receipt{}, err
+		}
+		return r.ToHub()
+	default:
+		var r index.Receipt
+		err := unmarshal(b, &r)
+		return r, err
+	}
+}
+
+// EncodePlugin encodes a plugin manifest in its apiVersion. It fails if the
+// plugin uses fields that its apiVersion does not have.
+func EncodePlugin(p index.Plugin) ([]byte, error) {
+	switch p.APIVersion {
+	case v1alpha2.APIVersion:
+		out, err := v1alpha2.PluginFromHub(p)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(out)
+	case v1beta1.APIVersion:
+		out, err := v1beta1.PluginFromHub(p)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(out)
+	default:
+		return yaml.Marshal(p)
+	}
+}
+
+// EncodeReceipt encodes a plugin receipt in the apiVersion of its plugin.
+func EncodeReceipt(r index.Receipt) ([]byte, error) {
+	switch r.APIVersion {
+	case v1alpha2.APIVersion:
+		out, err := v1alpha2.ReceiptFromHub(r)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(out)
+	case v1beta1.APIVersion:
+		out, err := v1beta1.ReceiptFromHub(r)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(out)
+	default:
+		return yaml.Marshal(r)
+	}
+}
+
+func decodeAPIVersion(b []byte) (string, error) {
+	var t metav1.TypeMeta
+	if err := unmarshal(b, &t); err != nil {
+		return "", err
+	}
+	return t.APIVersion, nil
+}
+
+func unmarshal(b []byte, v interface{}) error {
+	// TODO(ahmetb): when we have a stable API that won't add new fields,
+	// we can consider failing on unknown fields. Currently, disabling due to
+	// incremental field additions to plugin manifests independently from the
+	// installed version of krew.
+	// yaml.UnmarshalStrict()
+	return yaml.Unmarshal(b, v)
+}
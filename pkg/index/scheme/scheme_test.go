@@ -0,0 +1,351 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheme
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/krew/internal/testutil"
+	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/v1alpha2"
+	"sigs.k8s.io/krew/pkg/index/v1beta1"
+)
+
+const unknownAPIVersion = "krew.googlecontainertools.github.com/v2"
+
+const manifest = `apiVersion: %s
+kind: Plugin
+metadata:
+  name: foo
+spec:
+  version: v1.0.0
+  shortDescription: foo
+  platforms:
+  - uri: https://example.com/foo.tar.gz
+    sha256: deadbeef
+    bin: foo.sh
+    files:
+    - from: "*"
+      to: "."
+    selector:
+      matchLabels:
+        os: linux
+`
+
+const manifestWithNewFields = `apiVersion: %s
+kind: Plugin
+metadata:
+  name: foo
+spec:
+  version: v1.0.0
+  shortDescription: foo
+  deprecated: use bar instead
+  replacedBy: bar
+  platforms:
+  - uri: https://example.com/foo.tar.gz
+    sha256: deadbeef
+    bin: foo.sh
+    completion: complete.sh
+    files:
+    - from: "*"
+      to: "."
+    executables:
+    - name: foo-baz
+      bin: baz.sh
+      completion: complete-baz.sh
+    selector:
+      matchLabels:
+        os: linux
+`
+
+const receipt = `apiVersion: %s
+kind: Plugin
+metadata:
+  name: foo
+spec:
+  version: v1.0.0
+  shortDescription: foo
+  platforms:
+  - uri: https://example.com/foo.tar.gz
+    sha256: deadbeef
+    bin: foo.sh
+    files: null
+status:
+  source:
+    name: custom
+`
+
+const receiptWithNewFields = `apiVersion: %s
+kind: Plugin
+metadata:
+  name: foo
+spec:
+  version: v1.0.0
+  shortDescription: foo
+  platforms:
+  - uri: https://example.com/foo.tar.gz
+    sha256: deadbeef
+    bin: foo.sh
+    files: null
+    executables:
+    - name: foo-baz
+      bin: baz.sh
+status:
+  source:
+    name: custom
+  executables:
+  - foo-baz
+`
+
+func TestDecodePlugin(t *testing.T) {
+	tests := []struct {
+		name           string
+		apiVersion     string
+		wantDeprecated string
+		wantExecutable bool
+	}{
+		{
+			name:       "v1alpha2 leaves newer fields empty",
+			apiVersion: v1alpha2.APIVersion,
+		},
+		{
+			name:           "v1beta1",
+			apiVersion:     v1beta1.APIVersion,
+			wantDeprecated: "use bar instead",
+			wantExecutable: true,
+		},
+		{
+			name:           "unknown apiVersion is decoded as is",
+			apiVersion:     unknownAPIVersion,
+			wantDeprecated: "use bar instead",
+			wantExecutable: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := DecodePlugin([]byte(fmt.Sprintf(manifestWithNewFields, tt.apiVersion)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.APIVersion != tt.apiVersion {
+				t.Errorf("apiVersion = %q, want %q", p.APIVersion, tt.apiVersion)
+			}
+			if p.Name != "foo" || p.Spec.Version != "v1.0.0" || len(p.Spec.Platforms) != 1 || p.Spec.Platforms[0].Bin != "foo.sh" {
+				t.Errorf("manifest is not decoded: %+v", p)
+			}
+			if p.Spec.Deprecated != tt.wantDeprecated {
+				t.Errorf("deprecated = %q, want %q", p.Spec.Deprecated, tt.wantDeprecated)
+			}
+			if got := len(p.Spec.Platforms[0].Executables) > 0; got != tt.wantExecutable {
+				t.Errorf("has executables = %v, want %v", got, tt.wantExecutable)
+			}
+		})
+	}
+
+	if _, err := DecodePlugin([]byte("apiVersion: [")); err == nil {
+		t.Error("expected error for invalid yaml")
+	}
+}
+
+func TestPlugin_roundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		newObj   func() interface{}
+	}{
+		{
+			name:     "v1alpha2",
+			manifest: fmt.Sprintf(manifest, v1alpha2.APIVersion),
+			newObj:   func() interface{} { return &v1alpha2.Plugin{} },
+		},
+		{
+			name:     "v1beta1",
+			manifest: fmt.Sprintf(manifest, v1beta1.APIVersion),
+			newObj:   func() interface{} { return &v1beta1.Plugin{} },
+		},
+		{
+			name:     "v1beta1 with newer fields",
+			manifest: fmt.Sprintf(manifestWithNewFields, v1beta1.APIVersion),
+			newObj:   func() interface{} { return &v1beta1.Plugin{} },
+		},
+		{
+			name:     "unknown apiVersion",
+			manifest: fmt.Sprintf(manifestWithNewFields, unknownAPIVersion),
+			newObj:   func() interface{} { return &index.Plugin{} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := DecodePlugin([]byte(tt.manifest))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := EncodePlugin(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, got := tt.newObj(), tt.newObj()
+			if err := yaml.Unmarshal([]byte(tt.manifest), want); err != nil {
+				t.Fatal(err)
+			}
+			if err := yaml.Unmarshal(b, got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("manifest changed in round trip:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReceipt_roundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		receipt string
+		newObj  func() interface{}
+	}{
+		{
+			name:    "v1alpha2",
+			receipt: fmt.Sprintf(receipt, v1alpha2.APIVersion),
+			newObj:  func() interface{} { return &v1alpha2.Receipt{} },
+		},
+		{
+			name:    "v1beta1 with newer fields",
+			receipt: fmt.Sprintf(receiptWithNewFields, v1beta1.APIVersion),
+			newObj:  func() interface{} { return &v1beta1.Receipt{} },
+		},
+		{
+			name:    "unknown apiVersion",
+			receipt: fmt.Sprintf(receiptWithNewFields, unknownAPIVersion),
+			newObj:  func() interface{} { return &index.Receipt{} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := DecodeReceipt([]byte(tt.receipt))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := EncodeReceipt(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, got := tt.newObj(), tt.newObj()
+			if err := yaml.Unmarshal([]byte(tt.receipt), want); err != nil {
+				t.Fatal(err)
+			}
+			if err := yaml.Unmarshal(b, got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("receipt changed in round trip:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncodePlugin_unsupportedFields(t *testing.T) {
+	v1alpha2Meta := metav1.TypeMeta{APIVersion: v1alpha2.APIVersion, Kind: "Plugin"}
+	v1beta1Meta := metav1.TypeMeta{APIVersion: v1beta1.APIVersion, Kind: "Plugin"}
+	tests := []struct {
+		name    string
+		plugin  index.Plugin
+		wantErr bool
+	}{
+		{
+			name:    "v1alpha2 without newer fields",
+			plugin:  testutil.NewPlugin().WithTypeMeta(v1alpha2Meta).V(),
+			wantErr: false,
+		},
+		{
+			name:    "v1alpha2 with deprecated",
+			plugin:  testutil.NewPlugin().WithTypeMeta(v1alpha2Meta).WithDeprecated("old").V(),
+			wantErr: true,
+		},
+		{
+			name:    "v1alpha2 with replacedBy",
+			plugin:  testutil.NewPlugin().WithTypeMeta(v1alpha2Meta).WithReplacedBy("bar").V(),
+			wantErr: true,
+		},
+		{
+			name: "v1alpha2 with executables",
+			plugin: testutil.NewPlugin().WithTypeMeta(v1alpha2Meta).
+				WithPlatforms(testutil.NewPlatform().WithExecutables(index.Executable{Name: "foo-bar", Bin: "bar.sh"}).V()).V(),
+			wantErr: true,
+		},
+		{
+			name: "v1alpha2 with completion",
+			plugin: testutil.NewPlugin().WithTypeMeta(v1alpha2Meta).
+				WithPlatforms(testutil.NewPlatform().WithCompletion("complete.sh").V()).V(),
+			wantErr: true,
+		},
+		{
+			name: "v1alpha2 with libc selector",
+			plugin: testutil.NewPlugin().WithTypeMeta(v1alpha2Meta).
+				WithPlatforms(testutil.NewPlatform().WithSelector(&metav1.LabelSelector{
+					MatchLabels: map[string]string{"os": "linux", "libc": "musl"}}).V()).V(),
+			wantErr: true,
+		},
+		{
+			name: "v1alpha2 with arm selector expression",
+			plugin: testutil.NewPlugin().WithTypeMeta(v1alpha2Meta).
+				WithPlatforms(testutil.NewPlatform().WithSelector(&metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key: "arm", Operator: metav1.LabelSelectorOpIn, Values: []string{"v7"}}}}).V()).V(),
+			wantErr: true,
+		},
+		{
+			name:    "v1beta1 with deprecated",
+			plugin:  testutil.NewPlugin().WithTypeMeta(v1beta1Meta).WithDeprecated("old").V(),
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EncodePlugin(tt.plugin)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EncodePlugin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncodeReceipt_unsupportedFields(t *testing.T) {
+	r := testutil.NewReceipt().
+		WithPlugin(testutil.NewPlugin().WithTypeMeta(metav1.TypeMeta{APIVersion: v1alpha2.APIVersion, Kind: "Plugin"}).V()).
+		WithStatus(index.ReceiptStatus{
+			Source:      index.SourceIndex{Name: "custom"},
+			Executables: []string{"foo-bar"},
+		}).V()
+	if _, err := EncodeReceipt(r); err == nil {
+		t.Error("expected error for a v1alpha2 receipt with executables in its status")
+	}
+}
+
+func TestIsSupportedAPIVersion(t *testing.T) {
+	for _, v := range []string{v1alpha2.APIVersion, v1beta1.APIVersion} {
+		if !IsSupportedAPIVersion(v) {
+			t.Errorf("expected %q to be supported", v)
+		}
+	}
+	if IsSupportedAPIVersion("krew.googlecontainertools.github.com/v1alpha1") {
+		t.Error("expected v1alpha1 not to be supported")
+	}
+}
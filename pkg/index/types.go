@@ -19,6 +19,10 @@ import (
 )
 
 // Plugin describes a plugin manifest file.
+//
+// The types of this package are the internal representation of manifests and
+// receipts, which every supported apiVersion is converted to. See the
+// versioned packages, such as v1alpha2, for the schemas of the files.
 type Plugin struct {
 	metav1.TypeMeta   `json:",inline" yaml:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata"`
@@ -34,6 +38,14 @@ type PluginSpec struct {
 	Caveats          string `json:"caveats,omitempty"`
 	Homepage         string `json:"homepage,omitempty"`
 
+	// Deprecated is a message that explains why the plugin should not be
+	// used anymore. The plugin is deprecated if it is set.
+	Deprecated string `json:"deprecated,omitempty"`
+	// ReplacedBy is the name of the plugin in the same index that replaces
+	// this plugin, e.g. after it was renamed. The plugin is deprecated if it
+	// is set.
+	ReplacedBy string `json:"replacedBy,omitempty"`
+
 	Platforms []Platform `json:"platforms,omitempty"`
 }
 
@@ -50,6 +62,28 @@ type Platform struct {
 	// The path is relative to the root of the installation folder.
 	// The binary will be linked after all FileOperations are executed.
 	Bin string `json:"bin"`
+
+	// Completion specifies the path to an executable that provides shell
+	// completion for the plugin, relative to the root of the installation
+	// folder. It is linked as kubectl_complete-<name> next to the plugin.
+	Completion string `json:"completion,omitempty"`
+
+	// Executables are additional executables of the plugin, such as the
+	// subcommands of a toolkit. Each is linked under its own plugin name.
+	Executables []Executable `json:"executables,omitempty"`
+}
+
+// Executable is an additional executable of a plugin.
+type Executable struct {
+	// Name is the plugin name the executable is linked as, e.g. "foo-bar"
+	// is linked as kubectl-foo_bar and called with "kubectl foo-bar".
+	Name string `json:"name"`
+	// Bin specifies the path to the executable, relative to the root of the
+	// installation folder.
+	Bin string `json:"bin"`
+	// Completion specifies the path to the completion executable of the
+	// executable, relative to the root of the installation folder.
+	Completion string `json:"completion,omitempty"`
 }
 
 // FileOperation specifies a file copying operation from plugin archive to the
@@ -69,6 +103,9 @@ type Receipt struct {
 // ReceiptStatus contains information about the installed plugin.
 type ReceiptStatus struct {
 	Source SourceIndex `json:"source"`
+	// Executables are the names of the additional executables that were
+	// linked when the plugin was installed.
+	Executables []string `json:"executables,omitempty"`
 }
 
 // SourceIndex contains information about the index a plugin was installed from.
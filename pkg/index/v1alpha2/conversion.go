@@ -0,0 +1,109 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/internal/conversion"
+)
+
+// ToHub converts the plugin to the internal representation. The fields that
+// were added after v1alpha2, such as deprecation, additional executables and
+// shell completion, are left empty.
+func (p Plugin) ToHub() (index.Plugin, error) {
+	var out index.Plugin
+	err := conversion.Convert(p, &out)
+	return out, err
+}
+
+// PluginFromHub converts the internal representation of a plugin to this
+// version. It fails if the plugin uses fields that v1alpha2 does not have.
+func PluginFromHub(p index.Plugin) (Plugin, error) {
+	if fields := unsupportedFields(p); len(fields) > 0 {
+		return Plugin{}, errors.Errorf("plugin %q uses fields that are not supported in %s: %s",
+			p.Name, APIVersion, strings.Join(fields, ", "))
+	}
+	var out Plugin
+	if err := conversion.Convert(p, &out); err != nil {
+		return Plugin{}, err
+	}
+	out.APIVersion = APIVersion
+	return out, nil
+}
+
+// ToHub converts the receipt to the internal representation. The additional
+// executables in its status are left empty, as v1alpha2 plugins have none.
+func (r Receipt) ToHub() (index.Receipt, error) {
+	var out index.Receipt
+	err := conversion.Convert(r, &out)
+	return out, err
+}
+
+// ReceiptFromHub converts the internal representation of a receipt to this
+// version. It fails if the receipt uses fields that v1alpha2 does not have.
+func ReceiptFromHub(r index.Receipt) (Receipt, error) {
+	if len(r.Status.Executables) > 0 {
+		return Receipt{}, errors.Errorf("receipt of plugin %q uses fields that are not supported in %s: status.executables",
+			r.Name, APIVersion)
+	}
+	p, err := PluginFromHub(r.Plugin)
+	if err != nil {
+		return Receipt{}, err
+	}
+	out := Receipt{Plugin: p}
+	if err := conversion.Convert(r.Status, &out.Status); err != nil {
+		return Receipt{}, err
+	}
+	return out, nil
+}
+
+// unsupportedFields returns the paths of the fields that are set in p but do
+// not exist in v1alpha2, including the selector keys that were added later.
+func unsupportedFields(p index.Plugin) []string {
+	var fields []string
+	if p.Spec.Deprecated != "" {
+		fields = append(fields, "spec.deprecated")
+	}
+	if p.Spec.ReplacedBy != "" {
+		fields = append(fields, "spec.replacedBy")
+	}
+	for i, pl := range p.Spec.Platforms {
+		if pl.Completion != "" {
+			fields = append(fields, fmt.Sprintf("spec.platforms[%d].completion", i))
+		}
+		if len(pl.Executables) > 0 {
+			fields = append(fields, fmt.Sprintf("spec.platforms[%d].executables", i))
+		}
+		if pl.Selector == nil {
+			continue
+		}
+		for _, key := range []string{"libc", "arm"} {
+			if _, ok := pl.Selector.MatchLabels[key]; ok {
+				fields = append(fields, fmt.Sprintf("spec.platforms[%d].selector.matchLabels.%s", i, key))
+			}
+		}
+		for j, expr := range pl.Selector.MatchExpressions {
+			if expr.Key == "libc" || expr.Key == "arm" {
+				fields = append(fields, fmt.Sprintf("spec.platforms[%d].selector.matchExpressions[%d]", i, j))
+			}
+		}
+	}
+	return fields
+}
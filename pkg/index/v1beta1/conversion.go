@@ -0,0 +1,56 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/krew/pkg/index"
+	"sigs.k8s.io/krew/pkg/index/internal/conversion"
+)
+
+// ToHub converts the plugin to the internal representation.
+func (p Plugin) ToHub() (index.Plugin, error) {
+	var out index.Plugin
+	err := conversion.Convert(p, &out)
+	return out, err
+}
+
+// PluginFromHub converts the internal representation of a plugin to this
+// version.
+func PluginFromHub(p index.Plugin) (Plugin, error) {
+	var out Plugin
+	if err := conversion.Convert(p, &out); err != nil {
+		return Plugin{}, err
+	}
+	out.APIVersion = APIVersion
+	return out, nil
+}
+
+// ToHub converts the receipt to the internal representation.
+func (r Receipt) ToHub() (index.Receipt, error) {
+	var out index.Receipt
+	err := conversion.Convert(r, &out)
+	return out, err
+}
+
+// ReceiptFromHub converts the internal representation of a receipt to this
+// version.
+func ReceiptFromHub(r index.Receipt) (Receipt, error) {
+	var out Receipt
+	if err := conversion.Convert(r, &out); err != nil {
+		return Receipt{}, err
+	}
+	out.APIVersion = APIVersion
+	return out, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1beta1 contains the v1beta1 schema of plugin manifests and
+// receipts. It adds the deprecation of plugins, additional executables and
+// shell completion to v1alpha2.
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIVersion is the apiVersion of the manifests and receipts of this package.
+const APIVersion = "krew.googlecontainertools.github.com/v1beta1"
+
+// Plugin describes a plugin manifest file.
+type Plugin struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata"`
+
+	Spec PluginSpec `json:"spec"`
+}
+
+// PluginSpec is the plugin specification.
+type PluginSpec struct {
+	Version          string `json:"version,omitempty"`
+	ShortDescription string `json:"shortDescription,omitempty"`
+	Description      string `json:"description,omitempty"`
+	Caveats          string `json:"caveats,omitempty"`
+	Homepage         string `json:"homepage,omitempty"`
+
+	// Deprecated is a message that explains why the plugin should not be
+	// used anymore. The plugin is deprecated if it is set.
+	Deprecated string `json:"deprecated,omitempty"`
+	// ReplacedBy is the name of the plugin in the same index that replaces
+	// this plugin, e.g. after it was renamed. The plugin is deprecated if it
+	// is set.
+	ReplacedBy string `json:"replacedBy,omitempty"`
+
+	Platforms []Platform `json:"platforms,omitempty"`
+}
+
+// Platform describes how to perform an installation on a specific platform
+// and how to match the target platform (os, arch).
+type Platform struct {
+	URI    string `json:"uri,omitempty"`
+	Sha256 string `json:"sha256,omitempty"`
+
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	Files    []FileOperation       `json:"files"`
+
+	// Bin specifies the path to the plugin executable.
+	// The path is relative to the root of the installation folder.
+	// The binary will be linked after all FileOperations are executed.
+	Bin string `json:"bin"`
+
+	// Completion specifies the path to an executable that provides shell
+	// completion for the plugin, relative to the root of the installation
+	// folder. It is linked as kubectl_complete-<name> next to the plugin.
+	Completion string `json:"completion,omitempty"`
+
+	// Executables are additional executables of the plugin, such as the
+	// subcommands of a toolkit. Each is linked under its own plugin name.
+	Executables []Executable `json:"executables,omitempty"`
+}
+
+// Executable is an additional executable of a plugin.
+type Executable struct {
+	// Name is the plugin name the executable is linked as, e.g. "foo-bar"
+	// is linked as kubectl-foo_bar and called with "kubectl foo-bar".
+	Name string `json:"name"`
+	// Bin specifies the path to the executable, relative to the root of the
+	// installation folder.
+	Bin string `json:"bin"`
+	// Completion specifies the path to the completion executable of the
+	// executable, relative to the root of the installation folder.
+	Completion string `json:"completion,omitempty"`
+}
+
+// FileOperation specifies a file copying operation from plugin archive to the
+// installation directory.
+type FileOperation struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Receipt describes a plugin receipt file.
+type Receipt struct {
+	Plugin `json:",inline" yaml:",inline"`
+
+	Status ReceiptStatus `json:"status"`
+}
+
+// ReceiptStatus contains information about the installed plugin.
+type ReceiptStatus struct {
+	Source SourceIndex `json:"source"`
+	// Executables are the names of the additional executables that were
+	// linked when the plugin was installed.
+	Executables []string `json:"executables,omitempty"`
+}
+
+// SourceIndex contains information about the index a plugin was installed from.
+type SourceIndex struct {
+	// Name is the configured name of an index a plugin was installed from.
+	Name string `json:"name"`
+}